@@ -0,0 +1,41 @@
+package main
+
+import (
+	"github.com/shameoff/rocket-in-console/pkg/audio"
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/render"
+	"github.com/shameoff/rocket-in-console/pkg/warnings"
+)
+
+// subscribeAudio озвучивает события полёта
+func subscribeAudio(game *gameState) {
+	events.Subscribe(game.events, func(e warnings.Raised) {
+		if e.Rule.Level == warnings.Warning {
+			game.audio.Play(audio.CueWarning)
+		} else {
+			game.audio.Play(audio.CueCaution)
+		}
+	})
+	events.Subscribe(game.events, func(e events.StageChanged) {
+		game.audio.Play(audio.CueStageSeparation)
+	})
+	events.Subscribe(game.events, func(e events.Crashed) {
+		game.audio.Play(audio.CueExplosion)
+	})
+}
+
+// updateAudio передаёт звуку тягу двигателя и продвигает его на шаг dt.
+// При ошибке вывода звук отключается до конца игры; сам бэкенд закрывает тот, кто его открыл.
+func updateAudio(game *gameState, dt float64) {
+	rocket := game.rocket
+	level := 0.0
+	if game.phase == phaseFlight && rocket.Fuel > 0 {
+		level = rocket.ThrustY / objects.RocketStages[rocket.ActiveStage].MaxThrustY
+	}
+	game.audio.SetThrust(level)
+	if err := game.audio.Advance(dt); err != nil {
+		game.audio = audio.Multi{}
+		game.notifier.Push(render.Notification{Text: "Audio disabled: " + err.Error(), Priority: render.PriorityCritical, Duration: toastDuration})
+	}
+}
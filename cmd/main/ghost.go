@@ -0,0 +1,116 @@
+package main
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/shameoff/rocket-in-console/pkg/autopilot"
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/ghost"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+	"github.com/shameoff/rocket-in-console/pkg/render"
+)
+
+// ghostColor - цвет призрака лучшего полёта
+var ghostColor = tcell.NewRGBColor(150, 150, 190)
+
+// ghostRace записывает полёт и сравнивает его с лучшим полётом по той же миссии.
+// Миссия - это мир (тело, погода, зерно), высота старта и высота, которую нужно набрать перед посадкой.
+type ghostRace struct {
+	dir      string                  // каталог лучших полётов (пустой - не сохранять)
+	bests    map[string]*ghost.Track // лучшие полёты, уже прочитанные или поставленные в этой сессии
+	best     *ghost.Track            // призрак текущего полёта (nil - соревноваться не с кем)
+	recorder *ghost.Recorder
+	match    int     // индекс образца призрака, с которым сравнивалась ракета
+	delta    float64 // отставание от призрака в секундах
+}
+
+// newGhostRace создаёт гонку с призраками, хранящимися в каталоге dir
+func newGhostRace(dir string) *ghostRace {
+	return &ghostRace{dir: dir, bests: make(map[string]*ghost.Track)}
+}
+
+// missionKey описывает миссию текущего полёта
+func missionKey(game *gameState) string {
+	return fmt.Sprintf("%s-%s-%d-start%.0f-goal%.0f",
+		physics.CurrentBody.Name, objects.CurrentWeather.Name, objects.CurrentWeather.Seed,
+		autopilot.Altitude(game.rocket, objects.GroundLevel), game.ascentAlt)
+}
+
+// start начинает запись нового полёта и выбирает призрака для него
+func (g *ghostRace) start(game *gameState) error {
+	mission := missionKey(game)
+	g.recorder = ghost.NewRecorder(mission, game.ascentAlt)
+	g.match, g.delta = 0, 0
+	best, ok := g.bests[mission]
+	if !ok && g.dir != "" {
+		var err error
+		if best, err = ghost.Load(g.dir, mission); err != nil {
+			// Испорченный рекорд не перечитываем при каждом рестарте: без призрака до нового рекорда
+			g.bests[mission], g.best = nil, nil
+			return err
+		}
+		g.bests[mission] = best
+	}
+	g.best = best
+	return nil
+}
+
+// update записывает положение ракеты и сравнивает его с призраком
+func (g *ghostRace) update(game *gameState) {
+	rocket := game.rocket
+	x, y := rocket.Position()
+	g.recorder.Record(game.flightTime, x, y, autopilot.Altitude(rocket, objects.GroundLevel), rocket.ActiveStage)
+	if g.best != nil {
+		g.delta, g.match = g.best.Delta(game.flightTime, x, y, g.match)
+	}
+}
+
+// subscribe засчитывает полёт при посадке после набора высоты и сохраняет рекорд
+func (g *ghostRace) subscribe(game *gameState) {
+	events.Subscribe(game.events, func(e events.Landed) {
+		x, y := game.rocket.Position()
+		track, ok := g.recorder.Finish(e.Time, x, y, game.rocket.ActiveStage)
+		if !ok {
+			return
+		}
+		previous := g.bests[track.Mission]
+		if previous != nil && previous.Time <= track.Time {
+			game.notifier.Push(render.Notification{Text: fmt.Sprintf("Mission time %.1fs (best %.1fs)", track.Time, previous.Time), Priority: render.PriorityNormal, Duration: toastDuration})
+			return
+		}
+		// Новый рекорд станет призраком со следующего полёта
+		g.bests[track.Mission] = track
+		text := fmt.Sprintf("New personal best: %.1fs", track.Time)
+		if previous != nil {
+			text += fmt.Sprintf(" (%+.1fs)", track.Time-previous.Time)
+		}
+		game.notifier.Push(render.Notification{Text: text, Priority: render.PriorityHigh, Duration: toastDuration})
+		if g.dir != "" {
+			if err := ghost.Save(g.dir, track); err != nil {
+				game.notifier.Push(render.Notification{Text: "Best flight not saved: " + err.Error(), Priority: render.PriorityCritical, Duration: toastDuration})
+			}
+		}
+	})
+}
+
+// draw рисует призрака в его положении на текущий момент полёта
+func (g *ghostRace) draw(screen tcell.Screen, game *gameState, cameraX, cameraY int) {
+	if g.best == nil {
+		return
+	}
+	s := g.best.At(game.flightTime)
+	sprite := (&objects.Rocket{ActiveStage: s.Stage}).GetRocketSprite()
+	render.DrawGhost(screen, int(math.Floor(s.X))-cameraX, int(math.Floor(s.Y))-cameraY, sprite, ghostColor)
+}
+
+// status возвращает строку HUD с отставанием от призрака
+func (g *ghostRace) status() []string {
+	if g.best == nil {
+		return nil
+	}
+	return []string{fmt.Sprintf("Ghost %+.1fs (best %.1fs)", g.delta, g.best.Time)}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/shameoff/rocket-in-console/pkg/autopilot"
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+const (
+	headlessStep        = 1.0 / 30 // фиксированный шаг симуляции без терминала
+	headlessReportEvery = 1.0      // период вывода телеметрии в секундах
+)
+
+// runHeadless прогоняет симуляцию без терминала с фиксированным шагом, печатает телеметрию
+// в out и возвращает код выхода: 0 - полёт завершён благополучно, 1 - ракета разбилась.
+func runHeadless(game *gameState, duration float64, out io.Writer) int {
+	landing := game.autopilot.Mode == autopilot.ModeLand
+	nextReport := 0.0
+	game.events.SubscribeAll(func(e events.Event) {
+		fmt.Fprintf(out, "EVENT %s\n", e)
+	})
+
+	for t := 0.0; t < duration; t += headlessStep {
+		stepWorld([]*gameState{game}, headlessStep)
+
+		if game.phase != phaseFlight {
+			report := game.crash
+			fmt.Fprintf(out, "CRASH at t=%.2fs: %s (impact speed %.2f, max altitude %.1f)\n",
+				t, report.Cause, report.ImpactSpeed, report.MaxAltitude)
+			return 1
+		}
+		if t >= nextReport {
+			printTelemetry(out, game, t)
+			nextReport += headlessReportEvery
+		}
+		if landing && game.autopilot.Mode == autopilot.ModeOff {
+			fmt.Fprintf(out, "LANDED at t=%.2fs, fuel %.1f, hull %.0f%%\n",
+				t, game.rocket.Fuel, game.rocket.Hull/objects.MaxHull*100)
+			return 0
+		}
+	}
+	printTelemetry(out, game, duration)
+	return 0
+}
+
+// printTelemetry выводит одну строку телеметрии - те же величины, что показывает HUD
+func printTelemetry(out io.Writer, game *gameState, t float64) {
+	r := game.rocket
+	fmt.Fprintf(out, "t=%6.2f alt=%8.2f vy=%7.2f vx=%7.2f thrust=%6.2f/%5.2f fuel=%8.1f stage=%d ap=%s\n",
+		t, autopilot.Altitude(r, objects.GroundLevel), r.Vy, r.Vx, r.ThrustY, r.ThrustX,
+		r.Fuel, r.ActiveStage, game.autopilot.Mode)
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/shameoff/rocket-in-console/pkg/autopilot"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/render"
+	"github.com/shameoff/rocket-in-console/pkg/warnings"
+)
+
+// defaultHUD - раскладка HUD по умолчанию; виджеты, указанные раньше, важнее
+const defaultHUD = "alerts,notifications,race,altimeter,vsi,horizon,fuel,throttle,radar,impact,gforce,stage,ghost,wind,autopilot,messages,stats"
+
+// hudAnchors - известные виджеты HUD и их якоря по умолчанию
+var hudAnchors = map[string]render.Anchor{
+	"stats":         render.AnchorTopRight,
+	"stage":         render.AnchorTopLeft,
+	"ghost":         render.AnchorTopLeft,
+	"race":          render.AnchorTopLeft,
+	"wind":          render.AnchorTopLeft,
+	"autopilot":     render.AnchorTopLeft,
+	"messages":      render.AnchorTopLeft,
+	"notifications": render.AnchorTopCenter,
+	"alerts":        render.AnchorTopCenter,
+	"altimeter":     render.AnchorTopRight,
+	"vsi":           render.AnchorBottomRight,
+	"horizon":       render.AnchorBottomRight,
+	"fuel":          render.AnchorBottomLeft,
+	"throttle":      render.AnchorBottomLeft,
+	"gforce":        render.AnchorBottomLeft,
+	"radar":         render.AnchorBottomCenter,
+	"impact":        render.AnchorBottomCenter,
+}
+
+// newHUD собирает HUD из раскладки spec вида "stats@top-right,stage,wind"
+func newHUD(game *gameState, spec string) (*render.HUD, error) {
+	config, err := render.ParseHUDConfig(spec, hudAnchors)
+	if err != nil {
+		return nil, err
+	}
+	hud := render.NewHUD(0, 0)
+	for _, c := range config {
+		hud.Add(c.Name, c.Anchor, hudWidget(game, c.Name))
+	}
+	return hud, nil
+}
+
+// hudWidget создаёт виджет HUD по имени
+func hudWidget(game *gameState, name string) render.Widget {
+	text := func(color tcell.Color, lines func() []string) render.Widget {
+		return &render.TextWidget{Lines: lines, Style: tcell.StyleDefault.Foreground(color)}
+	}
+	switch name {
+	case "stats":
+		return &render.TextWidget{
+			Lines: func() []string { return render.StatsLines(game.rocket, objects.GroundLevel) },
+			Style: tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack),
+		}
+	case "stage":
+		// Отображаем информацию о текущей ступени ракеты без пробела
+		return text(tcell.ColorYellow, func() []string {
+			return []string{"Stage:" + objects.RocketStages[game.rocket.ActiveStage].Name}
+		})
+	case "wind":
+		return text(tcell.ColorAqua, func() []string {
+			wind := render.WindText(&objects.CurrentWeather, float64(objects.GroundLevel-game.rocket.Y))
+			if wind == "" {
+				return nil
+			}
+			return []string{wind}
+		})
+	case "autopilot":
+		return text(tcell.ColorGreen, func() []string {
+			switch {
+			case game.autopilot.Mode != autopilot.ModeOff:
+				return []string{"Autopilot:" + game.autopilot.Mode.String()}
+			case game.scripts.pilotActive:
+				return []string{"Pilot:" + game.scripts.pilot.Name}
+			}
+			return nil
+		})
+	case "messages":
+		return text(tcell.ColorAqua, func() []string { return game.scripts.messages })
+	case "alerts":
+		return &render.AlertPanel{Alerts: func() []render.Alert {
+			if game.phase != phaseFlight {
+				return nil
+			}
+			var alerts []render.Alert
+			for _, a := range game.warnings.Active() {
+				alerts = append(alerts, render.Alert{
+					Text:    a.Rule.Message,
+					Warning: a.Rule.Level == warnings.Warning,
+					Age:     game.flightTime - a.Since,
+				})
+			}
+			return alerts
+		}}
+	case "race":
+		return text(tcell.ColorWhite, func() []string {
+			if game.race == nil {
+				return nil
+			}
+			return game.race.status()
+		})
+	case "ghost":
+		return text(ghostColor, game.ghosts.status)
+	case "impact":
+		return text(tcell.ColorOrange, func() []string {
+			p := &game.prediction
+			if !p.Impact {
+				return nil
+			}
+			return []string{fmt.Sprintf("Impact in %.1fs at %.1f", p.ImpactTime, p.ImpactSpeed)}
+		})
+	case "altimeter":
+		return game.instruments.Altimeter()
+	case "vsi":
+		return game.instruments.VerticalSpeed()
+	case "fuel":
+		return game.instruments.Fuel()
+	case "throttle":
+		return game.instruments.Throttle()
+	case "gforce":
+		return game.instruments.GForce()
+	case "radar":
+		return game.instruments.Radar()
+	case "horizon":
+		return game.instruments.Horizon()
+	default:
+		return game.notifier
+	}
+}
@@ -2,75 +2,243 @@
 package main
 
 import (
+	"flag"
+	"fmt"
+	"math"
 	"math/rand"
+	"os"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
+	"github.com/shameoff/rocket-in-console/pkg/achievements"
+	"github.com/shameoff/rocket-in-console/pkg/audio"
+	"github.com/shameoff/rocket-in-console/pkg/autopilot"
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/ghost"
 	"github.com/shameoff/rocket-in-console/pkg/input"
 	"github.com/shameoff/rocket-in-console/pkg/objects"
 	"github.com/shameoff/rocket-in-console/pkg/physics"
 	"github.com/shameoff/rocket-in-console/pkg/render"
+	"github.com/shameoff/rocket-in-console/pkg/script"
+	"github.com/shameoff/rocket-in-console/pkg/warnings"
 )
 
 const (
-	verticalStep     = 0.5
-	horizontalStep   = 0.5
-	thrustDecayRate  = 0.0 // Скорость снижения тяги при отпускании клавиши
-	decayRate        = 0.3
-	safeLandingSpeed = 20.0
+	verticalStep    = 0.5
+	horizontalStep  = 0.5
+	thrustDecayRate = 0.0 // Скорость снижения тяги при отпускании клавиши
+	decayRate       = 0.3
 )
 
-func processInput(rocket *objects.Rocket, eventQueue chan tcell.Event, dt float64) bool {
-	// Флаги нажатия клавиш в текущем цикле
-	upPressed := false
-	downPressed := false
-	leftPressed := false
-	rightPressed := false
-	stageToggled := false
+const (
+	initialFuel       = 10000.0
+	explosionDuration = 2.0 // длительность анимации взрыва перед показом отчёта
+	worldChunkCache   = 64  // сколько чанков мира держать в памяти
+	toastDuration     = 4.0 // сколько секунд показывается уведомление
+	maxNotifications  = 3   // сколько уведомлений видно одновременно
+	maxPlayers        = 2   // сколько игроков помещается на разделённом экране
+	padSpread         = 4   // на сколько колонок от центра площадки стоят ракеты двух игроков
+)
+
+// playerColors - цвета ракет игроков по порядку
+var playerColors = []tcell.Color{tcell.ColorWhite, tcell.ColorFuchsia, tcell.ColorLime, tcell.ColorAqua}
+
+// playerColor возвращает цвет ракеты игрока с порядковым номером i (с нуля)
+func playerColor(i int) tcell.Color {
+	return playerColors[i%len(playerColors)]
+}
+
+// gamePhase описывает текущую фазу игрового цикла
+type gamePhase int
+
+const (
+	phaseFlight    gamePhase = iota // обычный полёт
+	phaseExploding                  // идёт анимация взрыва
+	phaseCrashed                    // показан отчёт о крушении, ждём выбора игрока
+)
+
+// gameState хранит всё состояние игрока между кадрами. Мир (рельеф, погода, сущности)
+// общий для всех игроков и хранится в пакете objects.
+type gameState struct {
+	name        string      // имя игрока в гонке
+	color       tcell.Color // цвет ракеты игрока
+	padOffset   int         // смещение ракеты от центра стартовой площадки
+	rivals      []*gameState
+	race        *race // nil - игрок летает один
+	rocket      *objects.Rocket
+	hoverThrust float64
+	phase       gamePhase
+	phaseTimer  float64 // время, проведённое в текущей фазе
+	flightTime  float64
+	history     *objects.AltitudeHistory
+	crash       *objects.CrashReport
+	autopilot   *autopilot.Autopilot
+	ascentAlt   float64 // целевая высота подъёма для автопилота
+	scripts     *scripts
+	events      *events.Bus
+	monitor     physics.FlightMonitor
+	warnings    *warnings.Monitor
+	audio       audio.Backend
+	predict     float64            // горизонт прогноза траектории в секундах (0 - не прогнозировать)
+	prediction  physics.Prediction // прогноз траектории на текущий кадр
+	ghosts      *ghostRace
+
+	achievements *achievements.Tracker
+	notifier     *render.Notifier
+	instruments  *render.Instruments
+	hud          *render.HUD
+}
+
+// inputKeys - флаги нажатых клавиш одного игрока за текущий цикл
+type inputKeys struct {
+	up, down, left, right bool
+	stageToggled          bool
+	autopilotToggled      bool           // P: включить удержание высоты или выключить автопилот
+	autopilotMode         autopilot.Mode // выбранный клавишей режим автопилота (ModeOff - не выбран)
+}
+
+// frameInput - весь ввод за текущий цикл
+type frameInput struct {
+	players           [maxPlayers]inputKeys // в одиночной игре всё управление у первого игрока
+	respawn, newWorld bool
+	resized           bool // изменился размер терминала
+	width, height     int  // новый размер терминала
+}
+
+// newRocket создаёт ракету, стоящую на стартовой площадке со смещением offset от её центра
+func newRocket(hoverThrust float64, offset int) *objects.Rocket {
+	rocket := &objects.Rocket{
+		Vx:          0,
+		Vy:          0,
+		ThrustX:     0,
+		ThrustY:     hoverThrust,
+		Fuel:        initialFuel,
+		ActiveStage: 0,
+	}
+	rocket.SetPosition(
+		float64(objects.Ground.LaunchX+offset-len(objects.RocketSprite[0])/2),
+		float64(objects.Ground.SurfaceAt(objects.Ground.LaunchX+offset)-len(objects.RocketSprite)),
+	)
+	rocket.ResetDamage()
+	return rocket
+}
+
+// initWorld генерирует рельеф, декорации и погоду мира по зерну
+func initWorld(seed int64) {
+	objects.InitTerrain(seed)
+	objects.CurrentWeather.Reset(seed)
+	objects.Scenery = objects.NewWorld(seed, &objects.Ground, worldChunkCache)
+	if !physics.CurrentBody.HasVegetation {
+		objects.Scenery.TreesPerChunk = 0
+	}
+	if !physics.CurrentBody.HasClouds {
+		objects.Scenery.CloudsPerChunk = 0
+	}
+	objects.Particles = nil
+	objects.Entities = physics.NewEntityWorld(physics.CurrentBody)
+}
+
+// configureWorld выбирает небесное тело, погоду и интегратор, заводит часы и генерирует мир по зерну
+func configureWorld(seed int64, bodyName, weatherName, integratorName string, hour, dayLength float64) error {
+	weather, err := objects.NewWeather(weatherName, seed)
+	if err != nil {
+		return err
+	}
+	body, err := physics.BodyByName(bodyName)
+	if err != nil {
+		return err
+	}
+	integrator, err := physics.IntegratorByName(integratorName)
+	if err != nil {
+		return err
+	}
+	objects.CurrentWeather = weather
+	physics.CurrentBody = body
+	physics.CurrentIntegrator = integrator
+	objects.Clock = objects.NewDayClock(hour, dayLength)
+	initWorld(seed)
+	return nil
+}
+
+// readInput забирает из очереди все накопившиеся события; возвращает true, если нужно выйти.
+// На разделённом экране WASD и пробел управляют левым игроком, стрелки и Enter - правым,
+// а клавиши автопилота не действуют; в одиночной игре всё управление у одной ракеты.
+func readInput(eventQueue chan tcell.Event, split bool) (frameInput, bool) {
+	var input frameInput
+	left, right := &input.players[0], &input.players[0]
+	if split {
+		right = &input.players[1]
+	}
 
 	// Проверка событий клавиатуры
 	for {
 		select {
 		case ev := <-eventQueue:
 			switch ev := ev.(type) {
+			case *tcell.EventResize:
+				input.resized = true
+				input.width, input.height = ev.Size()
 			case *tcell.EventKey:
 				switch ev.Key() {
 				case tcell.KeyEscape, tcell.KeyCtrlC:
-					return true
+					return input, true
 				case tcell.KeyUp:
-					upPressed = true
+					right.up = true
 				case tcell.KeyDown:
-					downPressed = true
+					right.down = true
 				case tcell.KeyLeft:
-					leftPressed = true
+					right.left = true
 				case tcell.KeyRight:
-					rightPressed = true
+					right.right = true
+				case tcell.KeyEnter:
+					if split {
+						right.stageToggled = true
+					}
 				}
 
 				switch ev.Rune() {
 				case 'w', 'W':
-					upPressed = true
+					left.up = true
 				case 's', 'S':
-					downPressed = true
+					left.down = true
 				case 'a', 'A':
-					leftPressed = true
+					left.left = true
 				case 'd', 'D':
-					rightPressed = true
+					left.right = true
 				case ' ': // Использование руны пробела вместо tcell.KeySpace
-					stageToggled = true
+					left.stageToggled = true
+				case 'p', 'P':
+					left.autopilotToggled = !split
+				case 'l', 'L':
+					if !split {
+						left.autopilotMode = autopilot.ModeLand
+					}
+				case 'g', 'G':
+					if !split {
+						left.autopilotMode = autopilot.ModeAscent
+					}
+				case 'v', 'V':
+					if !split {
+						left.autopilotMode = autopilot.ModeVelocityHold
+					}
+				case 'r', 'R':
+					input.respawn = true
+				case 'n', 'N':
+					input.newWorld = true
 				case 'q', 'Q':
-					return true
+					return input, true
 				}
 			}
 		default:
 			// Выходим из цикла, если в очереди больше нет событий
-			goto processMovement
+			return input, false
 		}
 	}
+}
 
-processMovement:
+func applyThrust(rocket *objects.Rocket, keys inputKeys, dt float64) {
 	// Переключение ступеней
-	if stageToggled {
+	if keys.stageToggled {
 		rocket.ActiveStage = (rocket.ActiveStage + 1) % len(objects.RocketStages)
 	}
 
@@ -78,12 +246,12 @@ processMovement:
 	currentStage := objects.RocketStages[rocket.ActiveStage]
 
 	// Обработка вертикального движения с учётом макс. тяги текущей ступени
-	if upPressed {
+	if keys.up {
 		rocket.ThrustY += verticalStep
 		if rocket.ThrustY > currentStage.MaxThrustY {
 			rocket.ThrustY = currentStage.MaxThrustY
 		}
-	} else if downPressed {
+	} else if keys.down {
 		rocket.ThrustY -= verticalStep
 	} else {
 		// При отпускании клавиш, тяга быстро падает до нуля (не до гравитации!)
@@ -91,12 +259,12 @@ processMovement:
 	}
 
 	// Обработка горизонтального движения с учётом макс. тяги текущей ступени
-	if leftPressed {
+	if keys.left {
 		rocket.ThrustX -= horizontalStep
 		if rocket.ThrustX < -currentStage.MaxThrustX {
 			rocket.ThrustX = -currentStage.MaxThrustX
 		}
-	} else if rightPressed {
+	} else if keys.right {
 		rocket.ThrustX += horizontalStep
 		if rocket.ThrustX > currentStage.MaxThrustX {
 			rocket.ThrustX = currentStage.MaxThrustX
@@ -105,39 +273,297 @@ processMovement:
 		// Если клавиши не нажаты, плавно снижаем тягу
 		rocket.ThrustX += (0 - rocket.ThrustX) * thrustDecayRate * dt
 	}
+}
+
+// processInput обрабатывает ввод всех игроков в зависимости от фазы игры; возвращает true, если нужно выйти
+func processInput(games []*gameState, eventQueue chan tcell.Event, dt float64) bool {
+	input, quit := readInput(eventQueue, len(games) > 1)
+	if quit {
+		return true
+	}
+	if input.resized {
+		width, height := viewSize(input.width, input.height, len(games))
+		for _, game := range games {
+			game.hud.Resize(width, height)
+		}
+	}
 
+	crashed := false
+	for i, game := range games {
+		keys := input.players[i]
+		switch game.phase {
+		case phaseFlight:
+			if keys.up || keys.down || keys.left || keys.right || keys.autopilotToggled || keys.autopilotMode != autopilot.ModeOff {
+				game.scripts.pilotActive = false
+			}
+			handleAutopilotKeys(game, keys)
+			applyThrust(game.rocket, keys, dt)
+		case phaseCrashed:
+			crashed = true
+		}
+	}
+
+	// После победы в гонке R и N начинают её заново
+	finished := games[0].race != nil && games[0].race.winner != nil
+	switch {
+	case input.newWorld && (crashed || finished):
+		initWorld(rand.Int63())
+		restart(games)
+	case input.respawn && finished:
+		restart(games)
+	case input.respawn:
+		for _, game := range games {
+			if game.phase == phaseCrashed {
+				respawn(game)
+			}
+		}
+	}
 	return false
 }
 
-func updateGame(rocket *objects.Rocket, dt float64, hoverThrust float64) {
-	// Remove the gravityCutoff parameter since our new physics model handles this
-	physics.UpdateRocket(rocket, dt, objects.GroundLevel, hoverThrust)
+// restart возвращает всех игроков на старт и начинает гонку заново
+func restart(games []*gameState) {
+	for _, game := range games {
+		respawn(game)
+	}
+	if race := games[0].race; race != nil {
+		race.reset()
+	}
+}
+
+// handleAutopilotKeys включает и выключает автопилот; ручное управление тягой его отключает
+func handleAutopilotKeys(game *gameState, keys inputKeys) {
+	ap := game.autopilot
+	switch {
+	case keys.up || keys.down || keys.left || keys.right:
+		ap.Disengage()
+	case keys.autopilotMode != autopilot.ModeOff:
+		ap.Engage(keys.autopilotMode, game.rocket, objects.GroundLevel, game.ascentAlt)
+	case keys.autopilotToggled && ap.Mode != autopilot.ModeOff:
+		ap.Disengage()
+	case keys.autopilotToggled:
+		ap.Engage(autopilot.ModeAltitudeHold, game.rocket, objects.GroundLevel, game.ascentAlt)
+	}
+}
+
+// respawn возвращает ракету на стартовую площадку с полным баком
+func respawn(game *gameState) {
+	game.rocket = newRocket(game.hoverThrust, game.padOffset)
+	game.phase = phaseFlight
+	game.phaseTimer = 0
+	game.flightTime = 0
+	game.history.Reset()
+	game.crash = nil
+	game.autopilot.Disengage()
+	game.scripts.reset()
+	game.monitor.Reset(game.rocket, objects.GroundLevel)
+	game.warnings.Reset()
+	game.notifier.Clear()
+	game.instruments.Reset(game.rocket, objects.GroundLevel)
+	if err := game.ghosts.start(game); err != nil {
+		game.notifier.Push(render.Notification{Text: "Best flight not loaded: " + err.Error(), Priority: render.PriorityCritical, Duration: toastDuration})
+	}
+}
+
+func updateGame(game *gameState, dt float64) {
+	game.phaseTimer += dt
+	game.notifier.Update(dt)
+	if game.phase == phaseFlight {
+		rocket := game.rocket
+		game.scripts.updatePilot(game)
+		game.autopilot.Update(rocket, dt, objects.GroundLevel)
+		physics.ApplyWeather(rocket, &objects.CurrentWeather, dt, objects.GroundLevel)
+		physics.UpdateRocket(rocket, dt, objects.GroundLevel, game.hoverThrust)
+		physics.EmitExhaust(rocket, dt, objects.GroundLevel)
+		if physics.UpdateHeating(rocket, dt, objects.GroundLevel) {
+			crash(game, objects.CrashOverheat, math.Hypot(rocket.Vx, rocket.Vy))
+		}
+		game.flightTime += dt
+		game.history.Record(float64(objects.GroundLevel-rocket.Y), dt)
+		game.instruments.Update(rocket, objects.GroundLevel, dt)
+		game.ghosts.update(game)
+	} else if game.phase == phaseExploding && game.phaseTimer >= explosionDuration {
+		game.phase = phaseCrashed
+		game.phaseTimer = 0
+	}
+}
+
+// updateWorld продвигает общий для игроков мир: погоду, часы, сущности вокруг летящих ракет и частицы
+func updateWorld(games []*gameState, dt float64) {
+	var focus []objects.Focus
+	for _, game := range games {
+		if game.phase == phaseFlight {
+			focus = append(focus, objects.Focus{X: game.rocket.X, Altitude: objects.GroundLevel - game.rocket.Y})
+		}
+	}
+	if len(focus) > 0 {
+		objects.Entities.Populate(focus...)
+	}
+	objects.CurrentWeather.Advance(dt)
+	objects.Clock.Advance(dt)
+	physics.UpdateEntities(objects.Entities, dt)
+	physics.UpdateParticles(dt, objects.GroundLevel)
+}
+
+// stepWorld выполняет один шаг симуляции для всех игроков в общем мире
+func stepWorld(games []*gameState, dt float64) {
+	for _, game := range games {
+		updateGame(game, dt)
+	}
+	updateWorld(games, dt)
+	for _, game := range games {
+		stepGame(game, dt)
+	}
+	if race := games[0].race; race != nil {
+		race.update(dt)
+	}
+}
+
+// stepGame завершает шаг симуляции игрока: столкновения, скрипты и раздача событий
+func stepGame(game *gameState, dt float64) {
+	handleCollisions(game)
+	if game.phase == phaseFlight {
+		game.monitor.Update(game.rocket, objects.GroundLevel, game.flightTime, game.events)
+		game.warnings.Update(warnings.ReadingsOf(game.rocket, objects.GroundLevel, initialFuel), game.flightTime, game.events)
+	}
+	game.scripts.updateMission(game)
+	game.events.Dispatch()
+	stopWreck(game)
+	if game.achievements != nil && game.phase == phaseFlight {
+		rocket := game.rocket
+		err := game.achievements.Update(achievements.Flight{
+			Rocket:       rocket,
+			Altitude:     autopilot.Altitude(rocket, objects.GroundLevel),
+			FuelFraction: rocket.Fuel / initialFuel,
+		})
+		if err != nil {
+			game.notifier.Push(render.Notification{Text: "Achievements not saved: " + err.Error(), Priority: render.PriorityCritical, Duration: toastDuration})
+		}
+	}
+	updateAudio(game, dt)
+}
+
+// subscribeNotifications показывает уведомления о событиях полёта
+func subscribeNotifications(game *gameState) {
+	notify := func(key, text string, priority render.Priority, duration float64) {
+		game.notifier.Push(render.Notification{Key: key, Text: text, Priority: priority, Duration: duration})
+	}
+	events.Subscribe(game.events, func(e events.StageChanged) {
+		notify("stage", "Stage: "+objects.RocketStages[e.To].Name, render.PriorityLow, 2)
+	})
+	events.Subscribe(game.events, func(e events.AtmosphereLayer) {
+		notify("layer", "Entering the "+e.Name, render.PriorityNormal, 3)
+	})
+	events.Subscribe(game.events, func(e events.KarmanCrossed) {
+		if e.Ascending {
+			notify("layer", "Welcome to space", render.PriorityNormal, toastDuration)
+		} else {
+			notify("layer", "Atmospheric re-entry", render.PriorityHigh, toastDuration)
+		}
+	})
+	events.Subscribe(game.events, func(e events.Landed) {
+		notify("", "Touchdown", render.PriorityLow, 3)
+	})
+	// Нехватку топлива обнаруживает система предупреждений и публикует её событием полёта LowFuel
+	events.Subscribe(game.events, func(e warnings.Raised) {
+		if e.Rule.Name == warnings.LowFuelRule {
+			game.events.Publish(events.LowFuel{Time: e.Time, Fuel: game.rocket.Fuel})
+		}
+	})
+	events.Subscribe(game.events, func(e events.LowFuel) {
+		notify("fuel", "LOW FUEL", render.PriorityHigh, toastDuration)
+	})
+	events.Subscribe(game.events, func(e events.CosmicSpeed) {
+		if e.Reached {
+			notify("cosmic", "COSMIC SPEED!", render.PriorityHigh, 0)
+		} else {
+			game.notifier.Dismiss("cosmic")
+		}
+	})
+	events.Subscribe(game.events, func(e achievements.Unlocked) {
+		notify("", "Achievement: "+e.Achievement.Title, render.PriorityNormal, toastDuration)
+	})
+}
+
+// handleCollisions проверяет жёсткую посадку, удар о склон, опрокидывание и деревья и запускает последовательность крушения
+func handleCollisions(game *gameState) {
+	if game.phase != phaseFlight {
+		return
+	}
+	rocket := game.rocket
+	if rocket.ImpactSpeed > 0 || rocket.TerrainCollision {
+		landing := physics.JudgeLanding(rocket.ImpactSpeed, rocket.TerrainCollision, rocket.GroundSlope, rocket.Hull)
+		rocket.DamageHull(landing.Damage)
+		if landing.Crashed {
+			crash(game, landing.Cause, rocket.ImpactSpeed)
+			return
+		}
+	}
+	switch {
+	case hitsTree(rocket):
+		crash(game, objects.CrashTree, math.Hypot(rocket.Vx, rocket.Vy))
+	default:
+		if hit := physics.CollideEntities(objects.Entities, rocket); hit != nil {
+			crash(game, hit.Cause, hit.Speed)
+		}
+	}
+}
+
+// hitsTree проверяет столкновение ракеты с деревьями: сначала по AABB через индекс, затем по клеткам
+func hitsTree(rocket *objects.Rocket) bool {
+	rocketSprite := rocket.GetRocketSprite()
+	for _, tree := range objects.Scenery.TreesIn(objects.SpriteRect(rocket.X, rocket.Y, rocketSprite)) {
+		if objects.SpritesOverlap(rocket.X, rocket.Y, rocketSprite, tree.X, tree.Y, tree.Sprite) {
+			return true
+		}
+	}
+	return false
 }
 
-func handleCollisions(screen tcell.Screen, rocket *objects.Rocket) {
-	// Получаем текущий спрайт ракеты
+// crash фиксирует отчёт о крушении и запускает анимацию взрыва, не блокируя цикл
+func crash(game *gameState, cause objects.CrashCause, impactSpeed float64) {
+	rocket := game.rocket
 	rocketSprite := rocket.GetRocketSprite()
-	
-	if rocket.Y+len(rocketSprite) >= objects.GroundLevel && rocket.Vy > safeLandingSpeed {
-		screenWidth, screenHeight := screen.Size()
-		cameraX := rocket.X - screenWidth/2
-		cameraY := rocket.Y - screenHeight/2
-		render.DrawSprite(screen, rocket.X-cameraX, rocket.Y-cameraY, objects.ExplosionSprite, tcell.ColorRed, tcell.ColorBlack)
-		screen.Show()
-		time.Sleep(2 * time.Second)
-		rocket.X = objects.WorldWidth/2 - len(objects.RocketSprite[0])/2
-		rocket.Y = objects.GroundLevel - len(objects.RocketSprite)
-		rocket.Vx = 0
-		rocket.Vy = 0
-		rocket.Fuel = 100
-	}
-}
-
-func renderFrame(screen tcell.Screen, rocket *objects.Rocket) {
+	game.history.Record(float64(objects.GroundLevel-rocket.Y), 0)
+	game.crash = &objects.CrashReport{
+		Cause:           cause,
+		ImpactSpeed:     impactSpeed,
+		Altitude:        float64(objects.GroundLevel - rocket.Y),
+		MaxAltitude:     game.history.Max(),
+		FlightTime:      game.flightTime,
+		FuelLeft:        rocket.Fuel,
+		Stage:           objects.RocketStages[rocket.ActiveStage].Name,
+		AltitudeHistory: game.history.Samples(),
+		Hull:            rocket.Hull,
+		EngineHealth:    append([]float64(nil), rocket.EngineHealth...),
+		Temperature:     rocket.Temperature,
+	}
+	game.events.Publish(events.Crashed{Time: game.flightTime, Cause: cause, Speed: impactSpeed, Altitude: game.crash.Altitude})
+	objects.EmitExplosion(float64(rocket.X+len(rocketSprite[0])/2), float64(rocket.Y+len(rocketSprite)/2))
+	game.phase = phaseExploding
+	game.phaseTimer = 0
+}
+
+// stopWreck останавливает разбившуюся ракету. Вызывается после рассылки событий,
+// чтобы обработчики Crashed видели скорость и тягу в момент удара.
+func stopWreck(game *gameState) {
+	if game.phase == phaseFlight {
+		return
+	}
+	rocket := game.rocket
+	rocket.Vx = 0
+	rocket.Vy = 0
+	rocket.ThrustX = 0
+	rocket.ThrustY = 0
+}
+
+func renderFrame(screen tcell.Screen, game *gameState) {
+	rocket := game.rocket
 	screenWidth, screenHeight := screen.Size()
 	cameraX := rocket.X - screenWidth/2
 	cameraY := rocket.Y - screenHeight/2
-	skyColor := render.GetSkyColor(rocket, objects.GroundLevel)
+	skyColor := render.GetSkyColor(rocket, objects.GroundLevel, &objects.Clock)
+	render.AmbientLight = objects.Clock.Daylight()
 	screen.Clear()
 
 	// Устанавливаем фоновый цвет неба
@@ -148,34 +574,274 @@ func renderFrame(screen tcell.Screen, rocket *objects.Rocket) {
 		}
 	}
 
-	render.DrawClouds(screen, objects.Clouds, cameraX, cameraY, screenWidth, screenHeight)
-	render.DrawStars(screen, cameraX, cameraY, screenWidth, screenHeight, objects.Stars, objects.IsStarAt)
-	render.DrawGround(screen, cameraX, cameraY, screenWidth, screenHeight, objects.GroundLevel)
-	render.DrawTrees(screen, objects.Trees, cameraX, cameraY, screenWidth, screenHeight)
-	
+	// Небо рисуется от дальнего к ближнему: звёзды, солнце и луна, облака
+	starVisibility := render.StarVisibility(float64(objects.GroundLevel-rocket.Y), &objects.Clock)
+	render.DrawStars(screen, cameraX, cameraY, screenWidth, screenHeight, objects.IsStarAt, starVisibility)
+	render.DrawSunAndMoon(screen, &objects.Clock, screenWidth, screenHeight)
+	viewport := objects.Rect{X: cameraX, Y: cameraY, W: screenWidth, H: screenHeight}
+	// Облака снесены ветром: ищем их в области, сдвинутой против сноса, и рисуем со сдвигом
+	cloudDrift := int(math.Round(objects.CurrentWeather.CloudDrift))
+	cloudViewport := viewport
+	cloudViewport.X -= cloudDrift
+	render.DrawClouds(screen, objects.Scenery.CloudsIn(cloudViewport), cameraX-cloudDrift, cameraY, screenWidth, screenHeight)
+	render.DrawTerrain(screen, &objects.Ground, cameraX, cameraY, screenWidth, screenHeight)
+	render.DrawTrees(screen, objects.Scenery.TreesIn(viewport), cameraX, cameraY, screenWidth, screenHeight)
+	render.DrawEntities(screen, objects.Entities, cameraX, cameraY, screenWidth, screenHeight)
+	render.DrawParticles(screen, objects.Particles, cameraX, cameraY, screenWidth, screenHeight)
+	game.prediction = physics.Prediction{}
+	if game.phase == phaseFlight {
+		game.prediction = physics.PredictTrajectory(rocket, objects.GroundLevel, game.hoverThrust, game.predict)
+		render.DrawTrajectory(screen, &game.prediction, cameraX, cameraY, screenWidth, screenHeight)
+	}
+	for _, rival := range game.rivals {
+		if rival.phase == phaseFlight {
+			drawRival(screen, rival, cameraX, cameraY)
+		}
+	}
+
 	// Используем динамический спрайт ракеты вместо статичного
 	rocketSprite := rocket.GetRocketSprite()
-	render.DrawSprite(screen, rocket.X-cameraX, rocket.Y-cameraY, rocketSprite, tcell.ColorWhite, tcell.ColorBlack)
-	
-	render.DrawExhaust(screen, rocket, cameraX, cameraY)
-	render.DrawStats(screen, rocket, objects.GroundLevel)
-	
-	// Отображаем информацию о текущей ступени ракеты без пробела
-	stageName := objects.RocketStages[rocket.ActiveStage].Name
-	render.DrawText(screen, 1, 1, "Stage:" + stageName, tcell.StyleDefault.Foreground(tcell.ColorYellow))
-	
-	const cosmicSpeedThreshold = 100.0
-	if rocket.Vy > cosmicSpeedThreshold {
-		render.DrawNotificationBox(screen, screenWidth, "COSMIC SPEED!")
+	if game.phase == phaseFlight {
+		game.ghosts.draw(screen, game, cameraX, cameraY)
+		render.DrawSprite(screen, rocket.X-cameraX, rocket.Y-cameraY, rocketSprite, game.color, tcell.ColorBlack)
+	} else if game.phase == phaseExploding && game.phaseTimer < explosionDuration/4 {
+		// Короткая вспышка в начале взрыва, дальше работают только частицы
+		flashX := rocket.X + len(rocketSprite[0])/2 - len(objects.ExplosionSprite[0])/2
+		flashY := rocket.Y + len(rocketSprite)/2 - len(objects.ExplosionSprite)/2
+		render.DrawSprite(screen, flashX-cameraX, flashY-cameraY, objects.ExplosionSprite, tcell.ColorYellow, tcell.ColorRed)
+	}
+
+	game.hud.Draw(screen)
+
+	if game.phase == phaseCrashed {
+		render.DrawCrashReport(screen, game.crash)
+	}
+}
+
+// drawRival рисует ракету другого игрока с его именем над ней
+func drawRival(screen tcell.Screen, rival *gameState, cameraX, cameraY int) {
+	rocket := rival.rocket
+	x, y := rocket.X-cameraX, rocket.Y-cameraY
+	render.DrawSprite(screen, x, y, rocket.GetRocketSprite(), rival.color, tcell.ColorBlack)
+	_, _, style, _ := screen.GetContent(x, y-1)
+	render.DrawText(screen, x+1, y-1, rival.name, style.Foreground(rival.color))
+}
+
+// viewSize возвращает размер части экрана width x height, отведённой одному из players игроков
+func viewSize(width, height, players int) (int, int) {
+	// Между частями экрана остаётся колонка-разделитель
+	return (width - (players - 1)) / players, height
+}
+
+// renderPlayers делит экран по вертикали между игроками и рисует кадр каждого со своей камерой и HUD
+func renderPlayers(screen tcell.Screen, games []*gameState) {
+	screenWidth, screenHeight := screen.Size()
+	width, height := viewSize(screenWidth, screenHeight, len(games))
+	divider := tcell.StyleDefault.Foreground(tcell.ColorGray).Background(tcell.ColorBlack)
+	for i, game := range games {
+		x := i * (width + 1)
+		renderFrame(render.NewViewport(screen, x, 0, width, height), game)
+		if i > 0 {
+			for y := 0; y < height; y++ {
+				screen.SetContent(x-1, y, '│', nil, divider)
+			}
+		}
 	}
 	screen.Show()
 }
 
+// newGame создаёт состояние игрока с ракетой на стартовой площадке или на высоте startAltitude над ней
+// и смещением padOffset от центра площадки
+func newGame(startAltitude, ascentAltitude float64, padOffset int) *gameState {
+	hoverThrust := physics.CurrentBody.SurfaceGravity
+	game := &gameState{
+		color:       playerColor(0),
+		padOffset:   padOffset,
+		rocket:      newRocket(hoverThrust, padOffset),
+		hoverThrust: hoverThrust,
+		history:     objects.NewAltitudeHistory(0.5, 240),
+		autopilot:   autopilot.New(),
+		ascentAlt:   ascentAltitude,
+		scripts:     &scripts{},
+		events:      events.NewBus(),
+		warnings:    warnings.NewMonitor(warnings.Defaults(physics.SafeLandingSpeed)),
+		audio:       audio.Multi{},
+		ghosts:      newGhostRace(""),
+		notifier:    render.NewNotifier(maxNotifications),
+		instruments: render.NewInstruments(initialFuel, physics.SafeLandingSpeed),
+	}
+	game.rocket.SetPosition(game.rocket.PosX, game.rocket.PosY-startAltitude)
+	game.monitor.Reset(game.rocket, objects.GroundLevel)
+	game.instruments.Reset(game.rocket, objects.GroundLevel)
+	subscribeNotifications(game)
+	subscribeAudio(game)
+	game.ghosts.subscribe(game)
+	game.ghosts.start(game) // без каталога призраков ошибок чтения нет
+	return game
+}
+
+// flagSet возвращает true, если флаг name задан в командной строке явно
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "server" {
+		if err := runServer(os.Args[2:], os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	seed := flag.Int64("seed", 0, "world and weather seed (0 - random)")
+	weatherName := flag.String("weather", "calm", "weather preset: calm, breezy, stormy, jetstream")
+	bodyName := flag.String("body", "earth", "celestial body to launch from: earth, moon, mars")
+	startHour := flag.Float64("hour", 12, "time of day at mission start, in hours")
+	dayLength := flag.Float64("day-length", 600, "real seconds per game day (0 - time stands still)")
+	startAltitude := flag.Float64("start-altitude", 0, "altitude above the launch pad to start at")
+	ascentAltitude := flag.Float64("ascent-altitude", 1200, "target altitude of the autopilot ascent")
+	integratorName := flag.String("integrator", "symplectic", "physics integrator: euler, symplectic, verlet, rk4")
+	autopilotName := flag.String("autopilot", "off", "autopilot mode at start: off, hold, velocity, ascent, land")
+	headless := flag.Bool("headless", false, "run the simulation without a terminal and print telemetry")
+	duration := flag.Float64("duration", 120, "headless run duration in seconds")
+	pilotFile := flag.String("pilot", "", "Starlark pilot script that controls thrust")
+	missionFile := flag.String("mission", "", "Starlark mission script with event hooks")
+	hudSpec := flag.String("hud", defaultHUD, "HUD widgets in priority order, each optionally with @anchor (top-left, top-center, top-right, bottom-left, bottom-center, bottom-right)")
+	achievementsFile := flag.String("achievements", achievements.DefaultPath(), "file to keep achievements in (empty - do not save; headless runs do not save by default)")
+	warningsFile := flag.String("warnings", "", "JSON file with warning rules that override or extend the defaults")
+	ghostsDir := flag.String("ghosts", ghost.DefaultDir(), "directory to keep best flights for ghost racing in (empty - do not save; headless runs do not use it by default)")
+	predict := flag.Float64("predict", 10, "seconds of predicted trajectory to draw (0 - off)")
+	audioSpec := flag.String("audio", "bell", "audio backends: off, bell, wav:FILE, pipe:COMMAND (comma-separated)")
+	players := flag.Int("players", 1, "number of players on a vertically split screen: 1 or 2")
+	connect := flag.String("connect", "", "address of a game server to join (see the server command)")
+	playerName := flag.String("name", "", "player name in a network game (empty - assigned by the server)")
+	raceName := flag.String("race", "altitude", "two-player race goal: altitude (reach the ascent altitude) or pad (land on another pad)")
+	scriptSteps := flag.Uint64("script-steps", script.DefaultStepLimit, "max interpreter steps per script call")
+	flag.Parse()
+
+	// Прогоны без терминала по умолчанию не трогают прогресс и рекорды игрока
+	if *headless && !flagSet("achievements") {
+		*achievementsFile = ""
+	}
+	if *headless && !flagSet("ghosts") {
+		*ghostsDir = ""
+	}
+
 	rand.Seed(time.Now().UnixNano())
-	objects.InitStars(100)
-	objects.InitClouds(200)
-	objects.InitTrees(200)
+	if *seed == 0 {
+		*seed = rand.Int63()
+	}
+	if *connect != "" {
+		if *headless || *players > 1 {
+			fmt.Fprintln(os.Stderr, "a network game is played by one player in a terminal")
+			os.Exit(2)
+		}
+		if err := playOnline(*connect, *playerName, *hudSpec, *predict, *audioSpec); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := configureWorld(*seed, *bodyName, *weatherName, *integratorName, *startHour, *dayLength); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	apMode, err := autopilot.ParseMode(*autopilotName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	goal, err := parseRaceGoal(*raceName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if *players < 1 || *players > maxPlayers {
+		fmt.Fprintf(os.Stderr, "number of players must be from 1 to %d\n", maxPlayers)
+		os.Exit(2)
+	}
+	if *headless && *players > 1 {
+		fmt.Fprintln(os.Stderr, "headless mode supports a single player")
+		os.Exit(2)
+	}
+
+	padOffset := 0
+	if *players > 1 {
+		padOffset = -padSpread
+	}
+	game := newGame(*startAltitude, *ascentAltitude, padOffset)
+	game.predict = *predict
+	game.autopilot.Engage(apMode, game.rocket, objects.GroundLevel, game.ascentAlt)
+	game.scripts, err = loadScripts(*pilotFile, *missionFile, *scriptSteps)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	game.scripts.reset()
+	game.scripts.subscribe(game)
+	if *warningsFile != "" {
+		game.warnings.Rules, err = warnings.LoadRules(*warningsFile, game.warnings.Rules)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	game.hud, err = newHUD(game, *hudSpec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	game.ghosts.dir = *ghostsDir
+	if err := game.ghosts.start(game); err != nil {
+		game.notifier.Push(render.Notification{Text: "Best flight not loaded: " + err.Error(), Priority: render.PriorityCritical, Duration: toastDuration})
+	}
+	game.achievements, err = achievements.Load(*achievementsFile, game.events)
+	if err != nil {
+		// Испорченный файл не перезаписываем: играем с пустым прогрессом, который не сохраняется
+		game.notifier.Push(render.Notification{Text: "Achievements not loaded: " + err.Error(), Priority: render.PriorityCritical, Duration: toastDuration})
+		game.achievements, _ = achievements.Load("", game.events)
+	}
+
+	// Второй игрок стартует с той же площадки; скрипты, достижения, звук
+	// и сохранение рекордов остаются за первым
+	games := []*gameState{game}
+	if *players > 1 {
+		rival := newGame(*startAltitude, *ascentAltitude, padSpread)
+		rival.color = playerColor(1)
+		rival.predict = *predict
+		rival.warnings.Rules = game.warnings.Rules
+		if rival.hud, err = newHUD(rival, *hudSpec); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		game.name, rival.name = "P1", "P2"
+		game.rivals, rival.rivals = []*gameState{rival}, []*gameState{game}
+		games = append(games, rival)
+		newRace(goal, *ascentAltitude, games)
+	}
+
+	if *headless {
+		game.scripts.echo = os.Stdout
+		// Без терминала звонить некуда, но PCM-вывод работает
+		backend, err := audio.Open(*audioSpec, nil)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		game.audio = backend
+		code := runHeadless(game, *duration, os.Stdout)
+		if err := backend.Close(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(code)
+	}
 
 	// Инициализация tcell
 	screen, err := tcell.NewScreen()
@@ -187,25 +853,24 @@ func main() {
 	}
 	defer screen.Fini()
 
+	game.audio, err = audio.Open(*audioSpec, screen.Beep)
+	if err != nil {
+		screen.Fini()
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	defer game.audio.Close()
+
 	// Настройка экрана
 	screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite))
 	screen.Clear()
+	screenWidth, screenHeight := screen.Size()
+	for _, game := range games {
+		game.hud.Resize(viewSize(screenWidth, screenHeight, len(games)))
+	}
 
 	eventQueue := input.EventQueue(screen)
 
-	hoverThrust := physics.StandardGravity
-
-	rocket := &objects.Rocket{
-		X:           objects.WorldWidth/2 - len(objects.RocketSprite[0])/2,
-		Y:           objects.GroundLevel - len(objects.RocketSprite),
-		Vx:          0,
-		Vy:          0,
-		ThrustX:     0,
-		ThrustY:     hoverThrust,
-		Fuel:        10000,
-		ActiveStage: 0,
-	}
-
 	lastTime := time.Now()
 
 	for {
@@ -213,13 +878,12 @@ func main() {
 		dt := now.Sub(lastTime).Seconds()
 		lastTime = now
 
-		if processInput(rocket, eventQueue, dt) {
+		if processInput(games, eventQueue, dt) {
 			return
 		}
 
-		updateGame(rocket, dt, hoverThrust)
-		handleCollisions(screen, rocket)
-		renderFrame(screen, rocket)
+		stepWorld(games, dt)
+		renderPlayers(screen, games)
 		time.Sleep(30 * time.Millisecond)
 	}
 }
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"io"
+	"math"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shameoff/rocket-in-console/pkg/netplay"
+)
+
+// netFrames - сколько шагов делает каждый клиент в сетевом тесте
+const netFrames = 90
+
+// maxCorrection - наибольший допустимый скачок своей ракеты при сверке со снимком
+const maxCorrection = 1.0
+
+// testNetServer запускает сервер сетевой игры на случайном порту и возвращает его адрес
+func testNetServer(t *testing.T) string {
+	t.Helper()
+	params := netplay.Welcome{
+		Seed:           5,
+		Body:           "earth",
+		Weather:        "calm",
+		Integrator:     "symplectic",
+		Hour:           12,
+		DayLength:      600,
+		AscentAltitude: 1200,
+		Race:           "altitude",
+	}
+	sim, err := newServerWorld(params, 0, raceAltitude, io.Discard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := netplay.NewServer(sim)
+	go server.Run(l)
+	t.Cleanup(func() { server.Close() })
+	return l.Addr().String()
+}
+
+// testNetSession подключает к серверу addr игрока name
+func testNetSession(t *testing.T, addr, name string) *netSession {
+	t.Helper()
+	client, err := netplay.Dial(addr, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	session, err := newNetSession(client, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return session
+}
+
+// fly шагает сессией s с клавишами keys и возвращает наибольший скачок своей ракеты при сверке со снимками
+func fly(t *testing.T, s *netSession, keys inputKeys) float64 {
+	var frame frameInput
+	frame.players[0] = keys
+	tick := s.client.Welcome.Tick
+	correction := 0.0
+	for i := 0; i < netFrames; i++ {
+		s.world.enter()
+		x, y := s.game.rocket.Position()
+		// До первого снимка ракета стоит не на своём месте площадки, этот скачок не считаем
+		synced := s.time > 0
+		err := s.sync()
+		if err == nil && s.game.phase == phaseFlight {
+			if nx, ny := s.game.rocket.Position(); synced {
+				correction = math.Max(correction, math.Hypot(nx-x, ny-y))
+			}
+			err = s.advance(frame, tick)
+		}
+		s.world.leave()
+		if err != nil {
+			t.Errorf("%s: %v", s.game.name, err)
+			return correction
+		}
+		time.Sleep(time.Duration(tick * float64(time.Second)))
+	}
+	return correction
+}
+
+// settle ждёт, пока сервер не подтвердит весь ввод сессии s
+func settle(t *testing.T, s *netSession) {
+	deadline := time.Now().Add(5 * time.Second)
+	for len(s.client.Pending()) > 0 {
+		if time.Now().After(deadline) {
+			t.Errorf("%d inputs never acknowledged", len(s.client.Pending()))
+			return
+		}
+		s.world.enter()
+		err := s.sync()
+		s.world.leave()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestNetPlayTwoClients(t *testing.T) {
+	addr := testNetServer(t)
+	a := testNetSession(t, addr, "a")
+	b := testNetSession(t, addr, "b")
+	startY := float64(a.game.rocket.Y)
+
+	// Сервер и оба клиента шагают одновременно, каждый в своём мире
+	var wg sync.WaitGroup
+	var corrections [2]float64
+	for i, fl := range []struct {
+		s    *netSession
+		keys inputKeys
+	}{{a, inputKeys{up: true}}, {b, inputKeys{right: true}}} {
+		wg.Add(1)
+		go func(i int, s *netSession, keys inputKeys) {
+			defer wg.Done()
+			corrections[i] = fly(t, s, keys)
+			settle(t, s)
+		}(i, fl.s, fl.keys)
+	}
+	wg.Wait()
+
+	for i, s := range []*netSession{a, b} {
+		if len(s.rivals) != 1 {
+			t.Errorf("client %d sees %d rivals, want 1", i+1, len(s.rivals))
+		}
+		if corrections[i] > maxCorrection {
+			t.Errorf("client %d: prediction corrected by %.3f, want at most %.1f", i+1, corrections[i], maxCorrection)
+		}
+		if s.game.phase != phaseFlight {
+			t.Errorf("client %d: rocket crashed", i+1)
+		}
+		// У площадки сервер держит стаи птиц, и клиенты видят их по снимкам
+		if n := s.world.entities.Sprites.Len(); n == 0 {
+			t.Errorf("client %d sees no obstacles", i+1)
+		}
+	}
+	a.world.enter()
+	climbed := startY - float64(a.game.rocket.Y)
+	a.world.leave()
+	if climbed <= 0 {
+		t.Errorf("rocket climbed %.1f with the throttle held, want above the pad", climbed)
+	}
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/shameoff/rocket-in-console/pkg/audio"
+	"github.com/shameoff/rocket-in-console/pkg/input"
+	"github.com/shameoff/rocket-in-console/pkg/netplay"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+	"github.com/shameoff/rocket-in-console/pkg/warnings"
+)
+
+// netSession - сетевая игра на стороне клиента. Своя ракета предсказывается локально
+// и сверяется с каждым снимком сервера; чужие ракеты, препятствия, крушения и гонка берутся из снимков.
+// Мир клиента подставляется в глобальные переменные только на время шага и отрисовки.
+type netSession struct {
+	client *netplay.Client
+	world  *world
+	game   *gameState
+	rivals map[int]*gameState
+	time   float64 // время мира по последнему снимку
+}
+
+// newNetSession генерирует мир по приветствию сервера и готовит игру для подключения client
+func newNetSession(client *netplay.Client, predict float64) (*netSession, error) {
+	welcome := client.Welcome
+	goal, err := parseRaceGoal(welcome.Race)
+	if err != nil {
+		return nil, err
+	}
+	w, err := newWorld(welcome)
+	if err != nil {
+		return nil, err
+	}
+	w.enter()
+	defer w.leave()
+	game := newGame(0, welcome.AscentAltitude, 0)
+	game.color = playerColor(welcome.ID - 1)
+	game.predict = predict
+	// Гонку ведёт сервер, клиент только показывает её по снимкам
+	game.race = &race{goal: goal, altitude: welcome.AscentAltitude, players: []*gameState{game}}
+	return &netSession{client: client, world: w, game: game, rivals: make(map[int]*gameState)}, nil
+}
+
+// step делает шаг клиента: сверяет предсказание с новым снимком, отправляет ввод
+// и сразу применяет его к своей ракете, не дожидаясь ответа сервера
+func (s *netSession) step(frame frameInput, dt float64) error {
+	s.world.enter()
+	defer s.world.leave()
+	if err := s.sync(); err != nil {
+		return err
+	}
+	return s.advance(frame, dt)
+}
+
+// sync применяет новый снимок сервера, если он пришёл
+func (s *netSession) sync() error {
+	snapshot, err := s.client.Poll()
+	if err != nil {
+		return err
+	}
+	if snapshot != nil {
+		s.apply(snapshot)
+	}
+	return nil
+}
+
+// advance отправляет ввод игрока и продвигает предсказание своей ракеты и эффекты на dt
+func (s *netSession) advance(frame frameInput, dt float64) error {
+	keys := frame.players[0]
+	if _, err := s.client.Send(netInput(keys, frame.respawn)); err != nil {
+		return err
+	}
+
+	game := s.game
+	game.phaseTimer += dt
+	game.notifier.Update(dt)
+	if game.phase == phaseFlight {
+		rocket := game.rocket
+		predictRocket(game, keys, dt)
+		physics.EmitExhaust(rocket, dt, objects.GroundLevel)
+		game.flightTime += dt
+		game.history.Record(float64(objects.GroundLevel-rocket.Y), dt)
+		game.instruments.Update(rocket, objects.GroundLevel, dt)
+		game.monitor.Update(rocket, objects.GroundLevel, game.flightTime, game.events)
+		game.warnings.Update(warnings.ReadingsOf(rocket, objects.GroundLevel, initialFuel), game.flightTime, game.events)
+	} else if game.phase == phaseExploding && game.phaseTimer >= explosionDuration {
+		game.phase = phaseCrashed
+		game.phaseTimer = 0
+	}
+	for _, rival := range game.rivals {
+		if rival.phase == phaseFlight {
+			physics.EmitExhaust(rival.rocket, dt, objects.GroundLevel)
+		}
+	}
+	physics.UpdateParticles(dt, objects.GroundLevel)
+	game.events.Dispatch()
+	stopWreck(game)
+	updateAudio(game, dt)
+	return nil
+}
+
+// apply переносит снимок в локальное состояние: время мира, препятствия, ракеты соперников, свою ракету и гонку
+func (s *netSession) apply(snapshot *netplay.Snapshot) {
+	if delta := snapshot.Time - s.time; delta > 0 {
+		objects.CurrentWeather.Advance(delta)
+		objects.Clock.Advance(delta)
+		s.time = snapshot.Time
+	}
+	applyEntities(snapshot.Entities)
+
+	game := s.game
+	players := []*gameState{game}
+	var rivals []*gameState
+	var winner *gameState
+	seen := make(map[int]bool)
+	for i := range snapshot.Players {
+		p := &snapshot.Players[i]
+		player := game
+		if p.ID == s.client.ID() {
+			s.applyOwn(p)
+		} else {
+			player = s.applyRival(p)
+			rivals = append(rivals, player)
+			players = append(players, player)
+			seen[p.ID] = true
+		}
+		if p.ID == snapshot.Winner {
+			winner = player
+		}
+	}
+	for id := range s.rivals {
+		if !seen[id] {
+			delete(s.rivals, id)
+		}
+	}
+	game.rivals = rivals
+
+	r := game.race
+	if winner != nil && r.winner != winner {
+		game.notifier.Push(winNotification(winner, snapshot.RaceTime))
+	}
+	r.players, r.time, r.winner = players, snapshot.RaceTime, winner
+}
+
+// applyEntities заменяет сущности клиента препятствиями из снимка. Клиент их не двигает
+// и не сталкивает с ракетами: снимки приходят каждый шаг, а столкновения решает сервер.
+func applyEntities(states []netplay.EntityState) {
+	w := objects.NewEntityWorld()
+	for _, state := range states {
+		e := w.Spawn()
+		w.Positions.Set(e, state.Position)
+		w.Velocities.Set(e, state.Velocity)
+		w.Sprites.Set(e, state.Sprite)
+		w.Lifetimes.Set(e, objects.Lifetime{Age: state.Age})
+	}
+	objects.Entities = w
+}
+
+// applyOwn сверяет свою ракету со снимком: берёт состояние сервера и повторяет поверх него
+// ввод, который сервер ещё не применил. Крушение и возврат на старт тоже решает сервер.
+func (s *netSession) applyOwn(p *netplay.PlayerState) {
+	game := s.game
+	game.name = p.Name
+	rocket := p.Rocket
+	switch {
+	case p.Crash != nil && game.phase == phaseFlight:
+		game.rocket = &rocket
+		crash(game, p.Crash.Cause, p.Crash.ImpactSpeed)
+		game.crash = p.Crash
+		return
+	case p.Crash != nil:
+		return
+	case game.phase != phaseFlight:
+		respawn(game)
+	}
+	game.rocket = &rocket
+	for _, in := range s.client.Pending() {
+		predictRocket(game, keysOf(in), s.client.Welcome.Tick)
+	}
+}
+
+// applyRival обновляет ракету соперника по снимку
+func (s *netSession) applyRival(p *netplay.PlayerState) *gameState {
+	rocket := p.Rocket
+	rival, ok := s.rivals[p.ID]
+	if !ok {
+		rival = &gameState{color: playerColor(p.ID - 1), phase: phaseFlight}
+		if p.Crash != nil {
+			rival.phase = phaseCrashed
+		}
+		s.rivals[p.ID] = rival
+	}
+	if p.Crash != nil && rival.phase == phaseFlight {
+		sprite := rocket.GetRocketSprite()
+		objects.EmitExplosion(float64(rocket.X+len(sprite[0])/2), float64(rocket.Y+len(sprite)/2))
+		rival.phase = phaseCrashed
+	} else if p.Crash == nil {
+		rival.phase = phaseFlight
+	}
+	rival.name, rival.rocket, rival.crash = p.Name, &rocket, p.Crash
+	return rival
+}
+
+// predictRocket повторяет для своей ракеты то, что сервер делает с ней за шаг:
+// тягу по вводу, ветер, физику и нагрев. Крушение определяет только сервер.
+func predictRocket(game *gameState, keys inputKeys, dt float64) {
+	rocket := game.rocket
+	applyThrust(rocket, keys, dt)
+	physics.ApplyWeather(rocket, &objects.CurrentWeather, dt, objects.GroundLevel)
+	physics.UpdateRocket(rocket, dt, objects.GroundLevel, game.hoverThrust)
+	physics.UpdateHeating(rocket, dt, objects.GroundLevel)
+}
+
+// netInput переводит нажатые клавиши в команду серверу
+func netInput(keys inputKeys, respawn bool) netplay.Input {
+	return netplay.Input{
+		Up:      keys.up,
+		Down:    keys.down,
+		Left:    keys.left,
+		Right:   keys.right,
+		Stage:   keys.stageToggled,
+		Respawn: respawn,
+	}
+}
+
+// keysOf переводит команду игрока обратно в нажатые клавиши
+func keysOf(in netplay.Input) inputKeys {
+	return inputKeys{up: in.Up, down: in.Down, left: in.Left, right: in.Right, stageToggled: in.Stage}
+}
+
+// playOnline подключается к серверу addr и играет, пока игрок не выйдет или соединение не прервётся
+func playOnline(addr, name, hudSpec string, predict float64, audioSpec string) error {
+	client, err := netplay.Dial(addr, name)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	welcome := client.Welcome
+	session, err := newNetSession(client, predict)
+	if err != nil {
+		return err
+	}
+	game := session.game
+	if game.hud, err = newHUD(game, hudSpec); err != nil {
+		return err
+	}
+
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return err
+	}
+	if err := screen.Init(); err != nil {
+		return err
+	}
+	defer screen.Fini()
+	if game.audio, err = audio.Open(audioSpec, screen.Beep); err != nil {
+		return err
+	}
+	defer game.audio.Close()
+	screen.SetStyle(tcell.StyleDefault.Background(tcell.ColorBlack).Foreground(tcell.ColorWhite))
+	screen.Clear()
+	game.hud.Resize(screen.Size())
+	eventQueue := input.EventQueue(screen)
+
+	// Клиент шагает с тем же фиксированным шагом, что и сервер, чтобы предсказание совпадало
+	ticker := time.NewTicker(time.Duration(welcome.Tick * float64(time.Second)))
+	defer ticker.Stop()
+	for range ticker.C {
+		frame, quit := readInput(eventQueue, false)
+		if quit {
+			return nil
+		}
+		if frame.resized {
+			game.hud.Resize(frame.width, frame.height)
+		}
+		if err := session.step(frame, welcome.Tick); err != nil {
+			return err
+		}
+		session.world.enter()
+		renderPlayers(screen, []*gameState{game})
+		session.world.leave()
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/shameoff/rocket-in-console/pkg/netplay"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// Параметры сетевой игры
+const (
+	defaultServerAddr = "127.0.0.1:7777"
+	maxNetPlayers     = 8
+)
+
+// serverWorld - авторитетная симуляция сетевой игры: каждый игрок - обычный gameState в общем мире.
+// Свой мир сервер подставляет в глобальные переменные только на время вызовов симуляции.
+type serverWorld struct {
+	params        netplay.Welcome // параметры мира, которые получает каждый игрок
+	world         *world
+	startAltitude float64
+	players       map[int]*gameState
+	order         []int // ID игроков в порядке подключения
+	race          *race
+	nextID        int
+	time          float64
+	log           io.Writer
+}
+
+// newServerWorld генерирует мир с параметрами params и создаёт его симуляцию
+func newServerWorld(params netplay.Welcome, startAltitude float64, goal raceGoal, log io.Writer) (*serverWorld, error) {
+	w, err := newWorld(params)
+	if err != nil {
+		return nil, err
+	}
+	return &serverWorld{
+		params:        params,
+		world:         w,
+		startAltitude: startAltitude,
+		players:       make(map[int]*gameState),
+		race:          newRace(goal, params.AscentAltitude, nil),
+		log:           log,
+	}, nil
+}
+
+// Join ставит ракету нового игрока на стартовую площадку; ракеты встают по очереди слева и справа от центра
+func (w *serverWorld) Join(name string) (netplay.Welcome, error) {
+	if len(w.order) >= maxNetPlayers {
+		return netplay.Welcome{}, fmt.Errorf("the server is full (%d players)", maxNetPlayers)
+	}
+	w.world.enter()
+	defer w.world.leave()
+	w.nextID++
+	id := w.nextID
+	offset := padSpread
+	if id%2 == 1 {
+		offset = -padSpread
+	}
+	game := newGame(w.startAltitude, w.params.AscentAltitude, offset)
+	game.name = name
+	if game.name == "" {
+		game.name = fmt.Sprintf("P%d", id)
+	}
+	w.players[id] = game
+	w.order = append(w.order, id)
+	w.race.join(game)
+	fmt.Fprintf(w.log, "%s joined\n", game.name)
+
+	welcome := w.params
+	welcome.ID = id
+	welcome.Time = w.time
+	return welcome, nil
+}
+
+// Leave убирает ракету игрока из мира
+func (w *serverWorld) Leave(id int) {
+	game, ok := w.players[id]
+	if !ok {
+		return
+	}
+	w.world.enter()
+	defer w.world.leave()
+	delete(w.players, id)
+	w.order = slices.DeleteFunc(w.order, func(other int) bool { return other == id })
+	w.race.leave(game)
+	fmt.Fprintf(w.log, "%s left\n", game.name)
+}
+
+// Control применяет ввод игрока так же, как processInput в локальной игре.
+// После победы в гонке команда возврата на старт начинает гонку заново для всех.
+func (w *serverWorld) Control(id int, in netplay.Input, dt float64) {
+	game, ok := w.players[id]
+	if !ok {
+		return
+	}
+	w.world.enter()
+	defer w.world.leave()
+	switch {
+	case in.Respawn && w.race.winner != nil:
+		restart(w.games())
+	case game.phase == phaseFlight:
+		applyThrust(game.rocket, keysOf(in), dt)
+	case game.phase == phaseCrashed && in.Respawn:
+		respawn(game)
+	}
+}
+
+// Step продвигает мир и всех игроков на dt
+func (w *serverWorld) Step(dt float64) {
+	w.world.enter()
+	defer w.world.leave()
+	w.time += dt
+	games := w.games()
+	if len(games) == 0 {
+		updateWorld(nil, dt)
+		return
+	}
+	stepWorld(games, dt)
+}
+
+// Snapshot возвращает состояние всех ракет, препятствий и гонки
+func (w *serverWorld) Snapshot() netplay.Snapshot {
+	snapshot := netplay.Snapshot{Time: w.time, RaceTime: w.race.time}
+	for _, id := range w.order {
+		game := w.players[id]
+		state := netplay.PlayerState{ID: id, Name: game.name, Rocket: *game.rocket}
+		// Снимок отправляется из другой горутины, пока симуляция идёт дальше
+		state.Rocket.EngineHealth = slices.Clone(game.rocket.EngineHealth)
+		if game.phase != phaseFlight {
+			state.Crash = game.crash
+		}
+		if game == w.race.winner {
+			snapshot.Winner = id
+		}
+		snapshot.Players = append(snapshot.Players, state)
+	}
+	entities := w.world.entities
+	entities.Sprites.Each(func(e objects.Entity, sprite *objects.Sprite) {
+		pos := entities.Positions.Get(e)
+		if pos == nil {
+			return
+		}
+		state := netplay.EntityState{Position: *pos, Sprite: *sprite}
+		if v := entities.Velocities.Get(e); v != nil {
+			state.Velocity = *v
+		}
+		if life := entities.Lifetimes.Get(e); life != nil {
+			state.Age = life.Age
+		}
+		snapshot.Entities = append(snapshot.Entities, state)
+	})
+	return snapshot
+}
+
+// games возвращает игроков в порядке подключения
+func (w *serverWorld) games() []*gameState {
+	games := make([]*gameState, 0, len(w.order))
+	for _, id := range w.order {
+		games = append(games, w.players[id])
+	}
+	return games
+}
+
+// runServer разбирает флаги команды server, генерирует мир и принимает игроков, пока сервер работает
+func runServer(args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("server", flag.ExitOnError)
+	listen := flags.String("listen", defaultServerAddr, "TCP address to accept players on")
+	seed := flags.Int64("seed", 0, "world and weather seed (0 - random)")
+	weatherName := flags.String("weather", "calm", "weather preset: calm, breezy, stormy, jetstream")
+	bodyName := flags.String("body", "earth", "celestial body to launch from: earth, moon, mars")
+	startHour := flags.Float64("hour", 12, "time of day at server start, in hours")
+	dayLength := flags.Float64("day-length", 600, "real seconds per game day (0 - time stands still)")
+	startAltitude := flags.Float64("start-altitude", 0, "altitude above the launch pad to start at")
+	ascentAltitude := flags.Float64("ascent-altitude", 1200, "altitude to reach in the altitude race")
+	integratorName := flags.String("integrator", "symplectic", "physics integrator: euler, symplectic, verlet, rk4")
+	raceName := flags.String("race", "altitude", "race goal: altitude (reach the ascent altitude) or pad (land on another pad)")
+	flags.Parse(args)
+
+	goal, err := parseRaceGoal(*raceName)
+	if err != nil {
+		return err
+	}
+	rand.Seed(time.Now().UnixNano())
+	if *seed == 0 {
+		*seed = rand.Int63()
+	}
+	params := netplay.Welcome{
+		Seed:           *seed,
+		Body:           *bodyName,
+		Weather:        *weatherName,
+		Integrator:     *integratorName,
+		Hour:           *startHour,
+		DayLength:      *dayLength,
+		AscentAltitude: *ascentAltitude,
+		Race:           *raceName,
+	}
+	sim, err := newServerWorld(params, *startAltitude, goal, out)
+	if err != nil {
+		return err
+	}
+
+	l, err := net.Listen("tcp", *listen)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Listening on %s (seed %d)\n", l.Addr(), *seed)
+	server := netplay.NewServer(sim)
+	return server.Run(l)
+}
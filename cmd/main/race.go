@@ -0,0 +1,171 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/shameoff/rocket-in-console/pkg/autopilot"
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+	"github.com/shameoff/rocket-in-console/pkg/render"
+)
+
+// raceGoal - цель гонки игроков
+type raceGoal int
+
+const (
+	raceAltitude raceGoal = iota // первым набрать высоту
+	racePad                      // первым сесть на другую посадочную площадку
+)
+
+// parseRaceGoal возвращает цель гонки по имени
+func parseRaceGoal(name string) (raceGoal, error) {
+	switch name {
+	case "altitude":
+		return raceAltitude, nil
+	case "pad":
+		return racePad, nil
+	}
+	return 0, fmt.Errorf("unknown race goal %q", name)
+}
+
+// race - гонка игроков в общем мире. Время гонки идёт с её начала и не сбрасывается,
+// когда разбившийся игрок возвращается на старт.
+type race struct {
+	goal     raceGoal
+	altitude float64 // высота, которую нужно набрать в гонке на высоту
+	players  []*gameState
+	time     float64
+	winner   *gameState // nil - гонка ещё идёт
+}
+
+// newRace начинает гонку игроков players
+func newRace(goal raceGoal, altitude float64, players []*gameState) *race {
+	r := &race{goal: goal, altitude: altitude}
+	for _, game := range players {
+		r.join(game)
+	}
+	return r
+}
+
+// join добавляет игрока в гонку; победу в гонке на площадку засчитывает его посадка
+func (r *race) join(game *gameState) {
+	r.players = append(r.players, game)
+	game.race = r
+	events.Subscribe(game.events, func(e events.Landed) {
+		if r.goal == racePad && e.OnPad && !onLaunchPad(game.rocket) {
+			r.finish(game)
+		}
+	})
+}
+
+// leave убирает игрока из гонки; если ушёл победитель, гонка начинается заново
+func (r *race) leave(game *gameState) {
+	r.players = slices.DeleteFunc(r.players, func(g *gameState) bool { return g == game })
+	if r.winner == game {
+		r.reset()
+	}
+}
+
+// reset начинает гонку заново
+func (r *race) reset() {
+	r.time = 0
+	r.winner = nil
+}
+
+// update продвигает время гонки и проверяет, не набрал ли кто-то высоту
+func (r *race) update(dt float64) {
+	if r.winner != nil {
+		return
+	}
+	r.time += dt
+	if r.goal != raceAltitude {
+		return
+	}
+	for _, game := range r.players {
+		if game.phase == phaseFlight && autopilot.Altitude(game.rocket, objects.GroundLevel) >= r.altitude {
+			r.finish(game)
+			return
+		}
+	}
+}
+
+// finish объявляет победителя всем игрокам
+func (r *race) finish(game *gameState) {
+	if r.winner != nil {
+		return
+	}
+	r.winner = game
+	for _, player := range r.players {
+		player.notifier.Push(winNotification(game, r.time))
+	}
+}
+
+// winNotification возвращает уведомление о победе игрока winner за время t
+func winNotification(winner *gameState, t float64) render.Notification {
+	return render.Notification{
+		Text:     fmt.Sprintf("%s wins the race in %.1fs", winner.name, t),
+		Priority: render.PriorityHigh,
+		Duration: toastDuration,
+	}
+}
+
+// status возвращает строки HUD: цель гонки и положение игроков или победителя
+func (r *race) status() []string {
+	lines := []string{"Race: land on another pad"}
+	if r.goal == raceAltitude {
+		lines[0] = fmt.Sprintf("Race to %.1f km", r.altitude*physics.GameToRealScale/1000)
+	}
+	if r.winner != nil {
+		return append(lines, fmt.Sprintf("%s won in %.1fs", r.winner.name, r.time))
+	}
+	for _, game := range r.players {
+		switch {
+		case game.phase != phaseFlight:
+			lines = append(lines, game.name+" crashed")
+		case r.goal == raceAltitude:
+			altitude := autopilot.Altitude(game.rocket, objects.GroundLevel)
+			lines = append(lines, fmt.Sprintf("%s %6.1f km", game.name, altitude*physics.GameToRealScale/1000))
+		default:
+			lines = append(lines, fmt.Sprintf("%s %6.1f km to a pad", game.name, padDistance(game.rocket)*physics.GameToRealScale/1000))
+		}
+	}
+	lines = append(lines, fmt.Sprintf("Time %.1fs", r.time))
+	return lines
+}
+
+// rocketCenter возвращает колонку центра ракеты
+func rocketCenter(rocket *objects.Rocket) int {
+	return rocket.X + len(rocket.GetRocketSprite()[0])/2
+}
+
+// onLaunchPad возвращает true, если ракета над стартовой площадкой
+func onLaunchPad(rocket *objects.Rocket) bool {
+	return abs(rocketCenter(rocket)-objects.Ground.LaunchX) <= objects.Ground.PadWidth/2
+}
+
+// padDistance возвращает расстояние по горизонтали до ближайшей площадки, кроме стартовой
+func padDistance(rocket *objects.Rocket) float64 {
+	ground := &objects.Ground
+	if ground.PadSpacing <= 0 {
+		return math.Inf(1)
+	}
+	offset := rocketCenter(rocket) - ground.LaunchX
+	k := int(math.Round(float64(offset) / float64(ground.PadSpacing)))
+	if k == 0 {
+		k = 1
+		if offset < 0 {
+			k = -1
+		}
+	}
+	return math.Abs(float64(offset - k*ground.PadSpacing))
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/script"
+)
+
+// maxScriptMessages - сколько последних сообщений скриптов показывать на экране
+const maxScriptMessages = 3
+
+// scripts хранит пользовательские скрипты пилота и миссии и их состояние между кадрами
+type scripts struct {
+	pilot       *script.Pilot
+	mission     *script.Mission
+	pilotActive bool      // пилот управляет ракетой; ручное управление его отключает
+	messages    []string  // последние сообщения скриптов для HUD
+	echo        io.Writer // если задан, сообщения дублируются сюда (headless-режим)
+}
+
+// loadScripts загружает скрипты пилота и миссии; пустое имя файла означает, что скрипта нет
+func loadScripts(pilotFile, missionFile string, stepLimit uint64) (*scripts, error) {
+	s := &scripts{}
+	if pilotFile != "" {
+		src, err := os.ReadFile(pilotFile)
+		if err != nil {
+			return nil, err
+		}
+		if s.pilot, err = script.LoadPilot(pilotFile, src, stepLimit); err != nil {
+			return nil, err
+		}
+		s.pilotActive = true
+		s.collect(s.pilot.Script)
+	}
+	if missionFile != "" {
+		src, err := os.ReadFile(missionFile)
+		if err != nil {
+			return nil, err
+		}
+		if s.mission, err = script.LoadMission(missionFile, src, stepLimit); err != nil {
+			return nil, err
+		}
+		s.collect(s.mission.Script)
+	}
+	return s, nil
+}
+
+// reset готовит скрипты к новому полёту
+func (s *scripts) reset() {
+	s.pilotActive = s.pilot != nil
+}
+
+// subscribe передаёт события полёта из шины обработчикам миссии
+func (s *scripts) subscribe(game *gameState) {
+	telemetry := func() script.Telemetry {
+		return script.NewTelemetry(game.rocket, objects.GroundLevel, game.flightTime)
+	}
+	events.Subscribe(game.events, func(e events.StageChanged) {
+		s.runMission(func(m *script.Mission) error { return m.OnStageChange(e.From, e.To, telemetry()) })
+	})
+	events.Subscribe(game.events, func(e events.Landed) {
+		s.runMission(func(m *script.Mission) error { return m.OnLanding(telemetry()) })
+	})
+	events.Subscribe(game.events, func(e events.Crashed) {
+		s.runMission(func(m *script.Mission) error { return m.OnCrash(string(e.Cause), telemetry()) })
+	})
+}
+
+// updatePilot передаёт пилоту телеметрию и применяет его команды к ракете
+func (s *scripts) updatePilot(game *gameState) {
+	if !s.pilotActive {
+		return
+	}
+	r := game.rocket
+	cmd, err := s.pilot.Tick(script.NewTelemetry(r, objects.GroundLevel, game.flightTime))
+	s.collect(s.pilot.Script)
+	if err != nil {
+		// Сломанный пилот отключается, управление возвращается игроку
+		s.pilotActive = false
+		s.addMessage(fmt.Sprintf("Pilot disabled: %v", err))
+		return
+	}
+	if cmd.Stage >= 0 && cmd.Stage < len(objects.RocketStages) {
+		r.ActiveStage = cmd.Stage
+	}
+	stage := objects.RocketStages[r.ActiveStage]
+	if cmd.SetThrustY {
+		r.ThrustY = clampThrust(cmd.ThrustY, 0, stage.MaxThrustY)
+	}
+	if cmd.SetThrustX {
+		r.ThrustX = clampThrust(cmd.ThrustX, -stage.MaxThrustX, stage.MaxThrustX)
+	}
+}
+
+// updateMission вызывает обработчик миссии on_tick
+func (s *scripts) updateMission(game *gameState) {
+	if game.phase != phaseFlight {
+		return
+	}
+	t := script.NewTelemetry(game.rocket, objects.GroundLevel, game.flightTime)
+	s.runMission(func(m *script.Mission) error { return m.OnTick(t) })
+}
+
+// runMission вызывает обработчик миссии, если она загружена; при ошибке миссия отключается
+func (s *scripts) runMission(hook func(m *script.Mission) error) {
+	if s.mission == nil {
+		return
+	}
+	err := hook(s.mission)
+	s.collect(s.mission.Script)
+	if err != nil {
+		s.mission = nil
+		s.addMessage(fmt.Sprintf("Mission disabled: %v", err))
+	}
+}
+
+// collect забирает сообщения скрипта в очередь HUD
+func (s *scripts) collect(sc *script.Script) {
+	for _, msg := range sc.TakeMessages() {
+		s.addMessage(msg)
+	}
+}
+
+func (s *scripts) addMessage(msg string) {
+	if s.echo != nil {
+		fmt.Fprintf(s.echo, "MSG %s\n", msg)
+	}
+	s.messages = append(s.messages, msg)
+	if len(s.messages) > maxScriptMessages {
+		s.messages = s.messages[len(s.messages)-maxScriptMessages:]
+	}
+}
+
+func clampThrust(v, lo, hi float64) float64 {
+	return min(max(v, lo), hi)
+}
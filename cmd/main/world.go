@@ -0,0 +1,85 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/shameoff/rocket-in-console/pkg/netplay"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+)
+
+// world - состояние мира, которое пакеты objects и physics держат в глобальных переменных:
+// рельеф, декорации, погода, часы, сущности, частицы, небесное тело и интегратор.
+// Локальная игра работает прямо с глобальными переменными. Сервер и клиент сетевой игры
+// владеют каждый своим миром и подставляют его в глобальные переменные на время шага
+// под общей блокировкой, поэтому в одном процессе они не трогают состояние друг друга.
+// Блокировка одна на процесс: сервер и клиент в одном процессе шагают и рисуют по очереди.
+// Мир создаётся только newWorld и передаётся по указателю: его декорации ссылаются на его поле ground.
+type world struct {
+	ground     objects.Terrain
+	scenery    *objects.World
+	weather    objects.Weather
+	clock      objects.DayClock
+	entities   *objects.EntityWorld
+	particles  []objects.Particle
+	body       *physics.Body
+	integrator *physics.Integrator
+}
+
+// worldMu охраняет глобальные переменные мира, пока в них подставлен мир сетевой игры
+var worldMu sync.Mutex
+
+// newWorld генерирует мир сетевой игры с параметрами params; глобальные переменные остаются прежними
+func newWorld(params netplay.Welcome) (*world, error) {
+	worldMu.Lock()
+	defer worldMu.Unlock()
+	saved := captureWorld()
+	defer saved.install()
+	if err := configureWorld(params.Seed, params.Body, params.Weather, params.Integrator, params.Hour, params.DayLength); err != nil {
+		return nil, err
+	}
+	w := captureWorld()
+	// Декорации генерируются по рельефу своего мира, а не по тому, что сейчас в objects.Ground
+	w.scenery.Terrain = &w.ground
+	return w, nil
+}
+
+// captureWorld возвращает мир, подставленный сейчас в глобальные переменные
+func captureWorld() *world {
+	return &world{
+		ground:     objects.Ground,
+		scenery:    objects.Scenery,
+		weather:    objects.CurrentWeather,
+		clock:      objects.Clock,
+		entities:   objects.Entities,
+		particles:  objects.Particles,
+		body:       physics.CurrentBody,
+		integrator: physics.CurrentIntegrator,
+	}
+}
+
+// install подставляет мир в глобальные переменные. Все поля независимы, поэтому порядок
+// присваиваний не важен: декорации мира из newWorld не читают objects.Ground, а мир
+// локальной игры, сохранённый captureWorld, ссылается на тот же objects.Ground, что и раньше.
+func (w *world) install() {
+	objects.Ground = w.ground
+	objects.Scenery = w.scenery
+	objects.CurrentWeather = w.weather
+	objects.Clock = w.clock
+	objects.Entities = w.entities
+	objects.Particles = w.particles
+	physics.CurrentBody = w.body
+	physics.CurrentIntegrator = w.integrator
+}
+
+// enter подставляет мир в глобальные переменные; до leave другие миры ждут
+func (w *world) enter() {
+	worldMu.Lock()
+	w.install()
+}
+
+// leave забирает изменения мира из глобальных переменных и отпускает их
+func (w *world) leave() {
+	*w = *captureWorld()
+	worldMu.Unlock()
+}
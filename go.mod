@@ -11,4 +11,5 @@ require (
 	golang.org/x/term v0.28.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
 	github.com/gdamore/tcell/v2 v2.8.1
+	go.starlark.net v0.0.0-20240725214946-42030a7cedce
 )
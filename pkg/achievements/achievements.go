@@ -0,0 +1,83 @@
+// Package achievements отслеживает достижения игрока между сессиями.
+// Достижения описаны декларативно списком All и проверяются по состоянию полёта каждый тик.
+package achievements
+
+import (
+	"fmt"
+
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+)
+
+// Flight - состояние полёта, по которому проверяются достижения
+type Flight struct {
+	Rocket       *objects.Rocket
+	Altitude     float64
+	FuelFraction float64        // доля оставшегося топлива (0..1)
+	Landing      *events.Landed // посадка на этом шаге (nil - не было)
+	Progress     *Progress      // накопленный между сессиями прогресс
+}
+
+// Achievement - достижение и условие его получения
+type Achievement struct {
+	ID          string
+	Title       string
+	Description string
+	Check       func(f *Flight) bool
+}
+
+// lowFuelFraction - доля топлива, посадка с которой считается посадкой "на парах"
+const lowFuelFraction = 0.01
+
+// All - все достижения игры
+var All = []Achievement{
+	{
+		ID: "first-landing", Title: "Touchdown", Description: "Land safely",
+		Check: func(f *Flight) bool { return f.Landing != nil },
+	},
+	{
+		ID: "pad-landing", Title: "Bullseye", Description: "Land on a landing pad",
+		Check: func(f *Flight) bool { return f.Landing != nil && f.Landing.OnPad },
+	},
+	{
+		ID: "karman", Title: "Edge of Space", Description: "Cross the Karman line",
+		Check: func(f *Flight) bool {
+			// У тела без атмосферы граница космоса условна
+			return physics.CurrentBody.InSpace(f.Altitude)
+		},
+	},
+	{
+		ID: "cosmic-speed", Title: "Cosmic Speed", Description: "Fall faster than the cosmic speed threshold",
+		Check: func(f *Flight) bool { return f.Rocket.Vy > physics.CosmicSpeedThreshold },
+	},
+	{
+		ID: "fumes", Title: "Running on Fumes", Description: "Land with under 1% fuel left",
+		Check: func(f *Flight) bool { return f.Landing != nil && f.FuelFraction < lowFuelFraction },
+	},
+	{
+		ID: "all-stages", Title: "Full Stack", Description: "Land with every rocket stage",
+		Check: func(f *Flight) bool { return len(f.Progress.LandedStages) >= len(objects.RocketStages) },
+	},
+	{
+		ID: "battered", Title: "Still Counts", Description: "Land with less than a quarter of the hull left",
+		Check: func(f *Flight) bool { return f.Landing != nil && f.Rocket.Hull < objects.MaxHull/4 },
+	},
+	{
+		ID: "moon", Title: "One Small Step", Description: "Land on the Moon",
+		Check: func(f *Flight) bool { return f.Landing != nil && physics.CurrentBody == &physics.Moon },
+	},
+	{
+		ID: "mars", Title: "Red Planet", Description: "Land on Mars",
+		Check: func(f *Flight) bool { return f.Landing != nil && physics.CurrentBody == &physics.Mars },
+	},
+}
+
+// Unlocked - событие получения достижения
+type Unlocked struct {
+	Achievement Achievement
+}
+
+func (e Unlocked) String() string {
+	return fmt.Sprintf("achievement unlocked: %s", e.Achievement.Title)
+}
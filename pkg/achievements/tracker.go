@@ -0,0 +1,112 @@
+package achievements
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// Progress - сохраняемый между сессиями прогресс игрока
+type Progress struct {
+	Unlocked     map[string]time.Time `json:"unlocked"`      // время получения достижения по ID
+	LandedStages map[string]bool      `json:"landed_stages"` // ступени, на которых игрок садился
+}
+
+// Tracker проверяет достижения, сохраняет прогресс в файл и публикует событие Unlocked
+type Tracker struct {
+	Progress Progress
+
+	path    string // пустой путь - прогресс не сохраняется
+	bus     *events.Bus
+	landing *events.Landed
+}
+
+// DefaultPath возвращает путь к файлу прогресса в каталоге настроек пользователя
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "rocket-in-console", "achievements.json")
+}
+
+// Load читает прогресс из path и подписывает трекер на события шины bus.
+// Отсутствующий файл означает, что достижений пока нет.
+func Load(path string, bus *events.Bus) (*Tracker, error) {
+	t := &Tracker{path: path, bus: bus}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if err == nil {
+			if err := json.Unmarshal(data, &t.Progress); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if t.Progress.Unlocked == nil {
+		t.Progress.Unlocked = make(map[string]time.Time)
+	}
+	if t.Progress.LandedStages == nil {
+		t.Progress.LandedStages = make(map[string]bool)
+	}
+	events.Subscribe(bus, func(e events.Landed) {
+		t.landing = &e
+	})
+	return t, nil
+}
+
+// Update проверяет ещё не полученные достижения по состоянию полёта f.
+// Посадка, пришедшая из шины после прошлой проверки, учитывается один раз.
+func (t *Tracker) Update(f Flight) error {
+	f.Landing, t.landing = t.landing, nil
+	f.Progress = &t.Progress
+	changed := false
+	if f.Landing != nil {
+		stage := objects.RocketStages[f.Rocket.ActiveStage].Name
+		if !t.Progress.LandedStages[stage] {
+			t.Progress.LandedStages[stage] = true
+			changed = true
+		}
+	}
+
+	for _, a := range All {
+		if _, ok := t.Progress.Unlocked[a.ID]; ok || !a.Check(&f) {
+			continue
+		}
+		t.Progress.Unlocked[a.ID] = time.Now()
+		t.bus.Publish(Unlocked{Achievement: a})
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return t.Save()
+}
+
+// Save записывает прогресс в файл
+func (t *Tracker) Save() error {
+	if t.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(t.Progress, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(t.path), 0o755); err != nil {
+		return err
+	}
+	// Пишем во временный файл и переименовываем, чтобы не испортить прогресс при сбое записи
+	tmp := t.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, t.path)
+}
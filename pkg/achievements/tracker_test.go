@@ -0,0 +1,131 @@
+package achievements
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// unlocked возвращает отсортированные ID полученных достижений
+func unlocked(t *Tracker) []string {
+	var ids []string
+	for id := range t.Progress.Unlocked {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// land сообщает трекеру о посадке ракеты r и проверяет достижения
+func land(t *testing.T, tracker *Tracker, bus *events.Bus, r *objects.Rocket, fuel float64, onPad bool) {
+	t.Helper()
+	bus.Publish(events.Landed{OnPad: onPad})
+	bus.Dispatch()
+	if err := tracker.Update(Flight{Rocket: r, FuelFraction: fuel}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLandingAchievements(t *testing.T) {
+	tests := []struct {
+		name  string
+		fuel  float64
+		onPad bool
+		hull  float64
+		want  []string
+	}{
+		{"terrain", 0.5, false, objects.MaxHull, []string{"first-landing"}},
+		{"pad", 0.5, true, objects.MaxHull, []string{"first-landing", "pad-landing"}},
+		{"fumes", lowFuelFraction / 2, false, objects.MaxHull, []string{"first-landing", "fumes"}},
+		{"almost fumes", lowFuelFraction * 2, false, objects.MaxHull, []string{"first-landing"}},
+		{"battered", 0.5, false, objects.MaxHull / 5, []string{"battered", "first-landing"}},
+	}
+	for _, tc := range tests {
+		bus := events.NewBus()
+		tracker, err := Load("", bus)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &objects.Rocket{}
+		r.ResetDamage()
+		r.Hull = tc.hull
+		land(t, tracker, bus, r, tc.fuel, tc.onPad)
+		if got := unlocked(tracker); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%s: unlocked %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestAllStagesAcrossSessions(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "achievements.json")
+	// Каждая сессия - новый трекер, прогресс переживает её только через файл
+	for stage := range objects.RocketStages {
+		bus := events.NewBus()
+		tracker, err := Load(path, bus)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := &objects.Rocket{ActiveStage: stage}
+		r.ResetDamage()
+		if err := tracker.Update(Flight{Rocket: r, FuelFraction: 0.5}); err != nil {
+			t.Fatal(err)
+		}
+		if len(tracker.Progress.LandedStages) != stage {
+			t.Fatalf("session %d: flight without a landing counted a stage: %v", stage, tracker.Progress.LandedStages)
+		}
+		land(t, tracker, bus, r, 0.5, false)
+		_, full := tracker.Progress.Unlocked["all-stages"]
+		if last := stage == len(objects.RocketStages)-1; full != last {
+			t.Errorf("session %d: all-stages unlocked = %v, want %v", stage, full, last)
+		}
+	}
+}
+
+func TestSaveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "progress", "achievements.json")
+	bus := events.NewBus()
+	tracker, err := Load(path, bus)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &objects.Rocket{}
+	r.ResetDamage()
+	land(t, tracker, bus, r, 0.5, true)
+	// Повторное сохранение заменяет файл целиком
+	if err := tracker.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := Load(path, events.NewBus())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unlocked(loaded), unlocked(tracker)) || !reflect.DeepEqual(loaded.Progress.LandedStages, tracker.Progress.LandedStages) {
+		t.Errorf("loaded progress %+v, want %+v", loaded.Progress, tracker.Progress)
+	}
+	for id, at := range tracker.Progress.Unlocked {
+		if !loaded.Progress.Unlocked[id].Equal(at) {
+			t.Errorf("%s unlocked at %v after loading, want %v", id, loaded.Progress.Unlocked[id], at)
+		}
+	}
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("progress directory holds %d files, want only the progress file", len(entries))
+	}
+
+	if err := os.WriteFile(path, []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path, events.NewBus()); err == nil {
+		t.Error("corrupt progress file loaded without an error")
+	}
+}
@@ -0,0 +1,138 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Cue - короткий звуковой сигнал игрового события
+type Cue int
+
+const (
+	CueCaution         Cue = iota // включилось предостережение
+	CueWarning                    // включилось предупреждение
+	CueStageSeparation            // переключена ступень
+	CueExplosion                  // ракета разбилась
+)
+
+// Backend - способ воспроизведения звука. Игра сообщает бэкенду о событиях и тяге,
+// а Advance продвигает звук на шаг симуляции: потоковые бэкенды генерируют в нём сэмплы.
+type Backend interface {
+	Play(cue Cue)
+	SetThrust(level float64) // тяга двигателя от 0 до 1
+	Advance(dt float64) error
+	Close() error
+}
+
+// DefaultSampleRate - частота дискретизации PCM-вывода
+const DefaultSampleRate = 22050
+
+// Open создаёт бэкенды по описанию spec - списку через запятую:
+//
+//	off           - без звука
+//	bell          - терминальный звонок на предупреждения (через beep)
+//	wav:PATH      - PCM в WAV-файл
+//	pipe:COMMAND  - PCM в WAV-потоке на стандартный ввод внешнего проигрывателя
+//
+// Если beep равен nil (нет терминала), bell пропускается.
+func Open(spec string, beep func() error) (Backend, error) {
+	var backends Multi
+	for _, item := range strings.Split(spec, ",") {
+		kind, arg, _ := strings.Cut(strings.TrimSpace(item), ":")
+		var backend Backend
+		var err error
+		switch kind {
+		case "", "off":
+			continue
+		case "bell":
+			if beep == nil {
+				continue
+			}
+			backend = NewBell(beep)
+		case "wav":
+			backend, err = openWAV(arg)
+		case "pipe":
+			backend, err = NewPipe(arg, DefaultSampleRate)
+		default:
+			err = fmt.Errorf("unknown audio backend %q", kind)
+		}
+		if err != nil {
+			backends.Close()
+			return nil, err
+		}
+		backends = append(backends, backend)
+	}
+	return backends, nil
+}
+
+// openWAV открывает PCM-бэкенд, пишущий в файл path
+func openWAV(path string) (Backend, error) {
+	if path == "" {
+		return nil, errors.New("wav audio backend needs a file name")
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	pcm, err := NewPCM(f, f, DefaultSampleRate)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return pcm, nil
+}
+
+// Multi раздаёт события нескольким бэкендам
+type Multi []Backend
+
+func (m Multi) Play(cue Cue) {
+	for _, b := range m {
+		b.Play(cue)
+	}
+}
+
+func (m Multi) SetThrust(level float64) {
+	for _, b := range m {
+		b.SetThrust(level)
+	}
+}
+
+func (m Multi) Advance(dt float64) error {
+	var errs []error
+	for _, b := range m {
+		errs = append(errs, b.Advance(dt))
+	}
+	return errors.Join(errs...)
+}
+
+func (m Multi) Close() error {
+	var errs []error
+	for _, b := range m {
+		errs = append(errs, b.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// Bell - терминальный звонок: звучит только на предупреждения
+type Bell struct {
+	beep func() error
+}
+
+// NewBell создаёт бэкенд, вызывающий beep (например, tcell.Screen.Beep)
+func NewBell(beep func() error) *Bell {
+	return &Bell{beep: beep}
+}
+
+func (b *Bell) Play(cue Cue) {
+	if cue == CueWarning {
+		b.beep()
+	}
+}
+
+func (b *Bell) SetThrust(float64) {}
+
+func (b *Bell) Advance(float64) error { return nil }
+
+func (b *Bell) Close() error { return nil }
@@ -0,0 +1,144 @@
+package audio
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"os/exec"
+	"strings"
+)
+
+// wavHeaderSize - размер заголовка WAV (RIFF + fmt + начало data)
+const wavHeaderSize = 44
+
+// streamSize - размер данных в заголовке потока, длина которого заранее неизвестна
+const streamSize = math.MaxUint32
+
+// PCM - бэкенд, синтезирующий звук и пишущий его 16-битным моно WAV
+type PCM struct {
+	synth   *Synth
+	w       io.Writer
+	closer  io.Closer
+	pending float64 // доля сэмпла, не вошедшая в предыдущий шаг
+	written int64   // байт аудиоданных записано
+	closed  bool
+	buf     []float64
+	out     []byte
+}
+
+// NewPCM пишет WAV в w. Если w умеет Seek (обычный файл), размеры в заголовке
+// исправляются при закрытии; иначе поток помечается как бесконечный.
+// closer закрывается в Close и может быть nil.
+func NewPCM(w io.Writer, closer io.Closer, sampleRate int) (*PCM, error) {
+	p := &PCM{synth: NewSynth(sampleRate), w: w, closer: closer}
+	if err := p.writeHeader(streamSize); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func (p *PCM) Play(cue Cue) {
+	p.synth.Play(cue)
+}
+
+func (p *PCM) SetThrust(level float64) {
+	p.synth.SetThrust(level)
+}
+
+// Advance синтезирует и записывает звук длительностью dt
+func (p *PCM) Advance(dt float64) error {
+	p.pending += dt * float64(p.synth.SampleRate)
+	n := int(p.pending)
+	p.pending -= float64(n)
+	if n == 0 {
+		return nil
+	}
+	if cap(p.buf) < n {
+		p.buf = make([]float64, n)
+		p.out = make([]byte, 2*n)
+	}
+	buf, out := p.buf[:n], p.out[:2*n]
+	p.synth.Fill(buf)
+	for i, v := range buf {
+		binary.LittleEndian.PutUint16(out[2*i:], uint16(int16(v*math.MaxInt16)))
+	}
+	written, err := p.w.Write(out)
+	p.written += int64(written)
+	return err
+}
+
+// Close дописывает размеры в заголовок, если это возможно, и закрывает вывод.
+// Повторный вызов ничего не делает.
+func (p *PCM) Close() error {
+	if p.closed {
+		return nil
+	}
+	p.closed = true
+	var err error
+	if seeker, ok := p.w.(io.WriteSeeker); ok && p.written+wavHeaderSize-8 <= streamSize {
+		if _, seekErr := seeker.Seek(0, io.SeekStart); seekErr == nil {
+			err = p.writeHeader(uint32(p.written))
+		}
+	}
+	if p.closer != nil {
+		err = errors.Join(err, p.closer.Close())
+	}
+	return err
+}
+
+// writeHeader пишет заголовок WAV с размером данных dataSize
+func (p *PCM) writeHeader(dataSize uint32) error {
+	const channels, bitsPerSample = 1, 16
+	rate := uint32(p.synth.SampleRate)
+	riffSize := uint32(streamSize)
+	if dataSize != streamSize {
+		riffSize = dataSize + wavHeaderSize - 8
+	}
+	header := make([]byte, 0, wavHeaderSize)
+	header = append(header, "RIFF"...)
+	header = binary.LittleEndian.AppendUint32(header, riffSize)
+	header = append(header, "WAVEfmt "...)
+	header = binary.LittleEndian.AppendUint32(header, 16) // размер блока fmt
+	header = binary.LittleEndian.AppendUint16(header, 1)  // PCM без сжатия
+	header = binary.LittleEndian.AppendUint16(header, channels)
+	header = binary.LittleEndian.AppendUint32(header, rate)
+	header = binary.LittleEndian.AppendUint32(header, rate*channels*bitsPerSample/8)
+	header = binary.LittleEndian.AppendUint16(header, channels*bitsPerSample/8)
+	header = binary.LittleEndian.AppendUint16(header, bitsPerSample)
+	header = append(header, "data"...)
+	header = binary.LittleEndian.AppendUint32(header, dataSize)
+	_, err := p.w.Write(header)
+	return err
+}
+
+// pipe - стандартный ввод внешнего проигрывателя; закрытие дожидается его завершения
+type pipe struct {
+	stdin io.WriteCloser
+	cmd   *exec.Cmd
+}
+
+func (p *pipe) Write(b []byte) (int, error) { return p.stdin.Write(b) }
+
+func (p *pipe) Close() error {
+	return errors.Join(p.stdin.Close(), p.cmd.Wait())
+}
+
+// NewPipe запускает проигрыватель command (например, "aplay -q" или "ffplay -nodisp -")
+// и передаёт ему звук WAV-потоком на стандартный ввод
+func NewPipe(command string, sampleRate int) (*PCM, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("pipe audio backend needs a player command")
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	p := &pipe{stdin: stdin, cmd: cmd}
+	return NewPCM(p, p, sampleRate)
+}
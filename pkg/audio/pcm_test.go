@@ -0,0 +1,92 @@
+package audio
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPCMWritesWAV(t *testing.T) {
+	const sampleRate = 8000
+	path := filepath.Join(t.TempDir(), "out.wav")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm, err := NewPCM(f, f, sampleRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pcm.SetThrust(1)
+	pcm.Play(CueExplosion)
+	// Две секунды неровными шагами: доли сэмпла копятся между шагами и не теряются
+	for _, dt := range []float64{0.25, 0.25, 0.5, 1.0 / 3, 2.0 / 3} {
+		if err := pcm.Advance(dt); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := pcm.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := pcm.Close(); err != nil {
+		t.Fatalf("second Close: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) < wavHeaderSize {
+		t.Fatalf("file is %d bytes, shorter than a WAV header", len(data))
+	}
+	// Сумма шагов в числах с плавающей точкой может недобрать последний сэмпл
+	samples := (len(data) - wavHeaderSize) / 2
+	if samples < 2*sampleRate-1 || samples > 2*sampleRate {
+		t.Fatalf("%d samples written, want %d", samples, 2*sampleRate)
+	}
+	dataSize := uint32(2 * samples)
+	if string(data[0:4]) != "RIFF" || string(data[8:16]) != "WAVEfmt " || string(data[36:40]) != "data" {
+		t.Fatalf("bad WAV chunk ids: %q", data[:wavHeaderSize])
+	}
+	if got := binary.LittleEndian.Uint32(data[4:]); got != dataSize+wavHeaderSize-8 {
+		t.Errorf("RIFF size %d, want %d", got, dataSize+wavHeaderSize-8)
+	}
+	if got := binary.LittleEndian.Uint32(data[40:]); got != dataSize {
+		t.Errorf("data size %d, want %d", got, dataSize)
+	}
+	if got := binary.LittleEndian.Uint32(data[24:]); got != sampleRate {
+		t.Errorf("sample rate %d, want %d", got, sampleRate)
+	}
+}
+
+func TestPCMStreamHeader(t *testing.T) {
+	// Вывод без Seek (труба) не может исправить заголовок и помечает поток бесконечным
+	var w pipeBuffer
+	pcm, err := NewPCM(&w, nil, DefaultSampleRate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pcm.Advance(0.1); err != nil {
+		t.Fatal(err)
+	}
+	if err := pcm.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := binary.LittleEndian.Uint32(w.data[40:]); got != streamSize {
+		t.Errorf("data size %d, want %d", got, uint32(streamSize))
+	}
+	if got, want := len(w.data), wavHeaderSize+2*DefaultSampleRate/10; got != want {
+		t.Errorf("stream length %d, want %d", got, want)
+	}
+}
+
+// pipeBuffer - вывод, который умеет только писать
+type pipeBuffer struct {
+	data []byte
+}
+
+func (b *pipeBuffer) Write(p []byte) (int, error) {
+	b.data = append(b.data, p...)
+	return len(p), nil
+}
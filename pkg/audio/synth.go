@@ -0,0 +1,132 @@
+package audio
+
+import "math"
+
+// Параметры синтеза
+const (
+	engineCutoff    = 120.0 // частота среза шума двигателя, Гц
+	engineHum       = 55.0  // частота гула двигателя, Гц
+	engineVolume    = 0.5
+	thrustSmoothing = 0.1 // секунды сглаживания громкости двигателя
+
+	explosionLength = 2.0
+	explosionDecay  = 2.5 // скорость затухания взрыва в секунду
+
+	separationLength = 0.4
+	separationTone   = 140.0 // частота удара при отделении ступени, Гц
+
+	beepTone    = 880.0
+	beepLength  = 0.15
+	beepGap     = 0.07
+	cautionTone = 660.0
+)
+
+// voice - звучащий сигнал
+type voice struct {
+	cue Cue
+	t   float64 // время с начала звучания
+	lp  float64 // состояние фильтра шума
+}
+
+// Synth генерирует моно-сэмплы от -1 до 1: гул двигателя и сигналы событий.
+// Шум детерминирован, поэтому один и тот же полёт всегда звучит одинаково.
+type Synth struct {
+	SampleRate int
+
+	thrust   float64 // целевая громкость двигателя
+	level    float64 // сглаженная громкость двигателя
+	engineLP float64
+	t        float64
+	noise    uint32
+	voices   []voice
+}
+
+// NewSynth создаёт синтезатор с частотой дискретизации sampleRate
+func NewSynth(sampleRate int) *Synth {
+	return &Synth{SampleRate: sampleRate, noise: 2463534242}
+}
+
+// Play начинает звучание сигнала cue
+func (s *Synth) Play(cue Cue) {
+	s.voices = append(s.voices, voice{cue: cue})
+}
+
+// SetThrust задаёт громкость двигателя
+func (s *Synth) SetThrust(level float64) {
+	s.thrust = max(0, min(1, level))
+}
+
+// Fill заполняет buf следующими сэмплами
+func (s *Synth) Fill(buf []float64) {
+	dt := 1 / float64(s.SampleRate)
+	engineAlpha := lowpassAlpha(engineCutoff, s.SampleRate)
+	for i := range buf {
+		s.level += (s.thrust - s.level) * min(1, dt/thrustSmoothing)
+		s.engineLP += (s.white() - s.engineLP) * engineAlpha
+		// Фильтрованный шум слабый, поэтому усиливаем его; гул добавляет двигателю тон
+		sample := s.level * engineVolume * (s.engineLP*4 + 0.3*math.Sin(2*math.Pi*engineHum*s.t))
+
+		alive := s.voices[:0]
+		for _, v := range s.voices {
+			value, done := s.voice(&v, dt)
+			sample += value
+			if !done {
+				alive = append(alive, v)
+			}
+		}
+		s.voices = alive
+
+		buf[i] = math.Tanh(sample) // мягкое ограничение вместо щелчков при перегрузке
+		s.t += dt
+	}
+}
+
+// voice возвращает очередной сэмпл сигнала v и true, когда сигнал закончился
+func (s *Synth) voice(v *voice, dt float64) (float64, bool) {
+	t := v.t
+	v.t += dt
+	switch v.cue {
+	case CueExplosion:
+		// Шум, который глохнет и становится всё ниже
+		env := math.Exp(-t * explosionDecay)
+		v.lp += (s.white() - v.lp) * lowpassAlpha(2000*env+60, s.SampleRate)
+		return env * v.lp * 3, t >= explosionLength
+	case CueStageSeparation:
+		// Глухой удар замков и шипение сброшенной ступени
+		thump := math.Sin(2*math.Pi*separationTone*t) * math.Exp(-t*25)
+		hiss := s.white() * 0.2 * math.Exp(-t*8)
+		return 0.8*thump + hiss, t >= separationLength
+	case CueWarning:
+		// Два коротких высоких сигнала
+		on := t < beepLength || (t >= beepLength+beepGap && t < 2*beepLength+beepGap)
+		return square(beepTone, t, on) * 0.25, t >= 2*beepLength+beepGap
+	default:
+		return square(cautionTone, t, t < beepLength) * 0.2, t >= beepLength
+	}
+}
+
+// white возвращает белый шум от -1 до 1 (xorshift32)
+func (s *Synth) white() float64 {
+	s.noise ^= s.noise << 13
+	s.noise ^= s.noise >> 17
+	s.noise ^= s.noise << 5
+	return float64(s.noise)/float64(math.MaxUint32)*2 - 1
+}
+
+// lowpassAlpha возвращает коэффициент однополюсного фильтра нижних частот с частотой среза cutoff
+func lowpassAlpha(cutoff float64, sampleRate int) float64 {
+	rc := 1 / (2 * math.Pi * cutoff)
+	dt := 1 / float64(sampleRate)
+	return dt / (rc + dt)
+}
+
+// square возвращает меандр частоты freq в момент t или тишину, если сигнал выключен
+func square(freq, t float64, on bool) float64 {
+	if !on {
+		return 0
+	}
+	if math.Sin(2*math.Pi*freq*t) >= 0 {
+		return 1
+	}
+	return -1
+}
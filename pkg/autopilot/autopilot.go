@@ -0,0 +1,297 @@
+// Package autopilot управляет тягой ракеты без участия игрока: удерживает высоту
+// и скорость, выводит ракету на заданную высоту и сажает её "самоубийственным" прожигом.
+// Пакет не зависит от терминала и может работать в headless-режиме.
+package autopilot
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+)
+
+// Mode - режим работы автопилота
+type Mode int
+
+const (
+	ModeOff          Mode = iota // автопилот выключен
+	ModeAltitudeHold             // удержание высоты (зависание)
+	ModeVelocityHold             // удержание вектора скорости
+	ModeAscent                   // подъём с гравитационным разворотом до заданной высоты
+	ModeLand                     // посадка с прожигом в последний момент
+)
+
+// String возвращает короткое название режима для HUD
+func (m Mode) String() string {
+	switch m {
+	case ModeAltitudeHold:
+		return "HOLD ALT"
+	case ModeVelocityHold:
+		return "HOLD VEL"
+	case ModeAscent:
+		return "ASCENT"
+	case ModeLand:
+		return "LAND"
+	default:
+		return "OFF"
+	}
+}
+
+// Параметры регуляторов
+const (
+	maxClimbRate     = 15.0 // максимальная вертикальная скорость в режиме удержания высоты
+	touchdownSpeed   = 3.0  // скорость касания, к которой стремится посадка
+	burnMargin       = 0.7  // доля доступного торможения, закладываемая в расчёт прожига
+	burnBuffer       = 2.0  // запас высоты при начале прожига
+	gravityTurnSpeed = 20.0 // горизонтальная скорость в конце гравитационного разворота
+	landedHeight     = 0.2  // высота над рельефом, ниже которой ракета считается севшей
+	landedSpeed      = 1.0  // вертикальная скорость, ниже которой ракета считается севшей
+	fullThrustAccel  = 1e3  // запрошенное ускорение, заведомо превышающее возможности двигателя
+	siteSearchRange  = 60   // на сколько колонок в стороны посадка ищет ровное место
+	siteApproachGain = 0.5  // горизонтальная скорость на колонку расстояния до места посадки
+	maxDriftSpeed    = 8.0  // наибольшая горизонтальная скорость подхода к месту посадки
+	siteTolerance    = 0.5  // насколько ракета может промахнуться мимо места посадки
+	siteHoverHeight  = 8.0  // высота над рельефом, на которой ракета идёт к месту посадки
+)
+
+// Autopilot читает состояние ракеты и выставляет ThrustX/ThrustY
+type Autopilot struct {
+	Mode           Mode
+	TargetAltitude float64 // целевая высота для удержания и подъёма
+	TargetVx       float64 // целевая горизонтальная скорость
+	TargetVUp      float64 // целевая вертикальная скорость (положительная - вверх)
+	SelectStage    bool    // при включении подъёма и посадки переключаться на ступень с наибольшим запасом тяги
+
+	altitudePID PID
+	vUpPID      PID
+	vxPID       PID
+	burning     bool // посадочный прожиг начат
+	siteX       int  // левая колонка ровного места посадки
+}
+
+// New создаёт выключенный автопилот с настроенными регуляторами
+func New() *Autopilot {
+	return &Autopilot{
+		SelectStage: true,
+		altitudePID: PID{Kp: 0.8, Ki: 0.05, Kd: 0, IntegralLimit: 50},
+		vUpPID:      PID{Kp: 2.5, Ki: 0.4, Kd: 0.05, IntegralLimit: 20},
+		vxPID:       PID{Kp: 1.5, Ki: 0.1, Kd: 0, IntegralLimit: 10},
+	}
+}
+
+// Engage включает режим mode, беря цели из текущего состояния ракеты.
+// Для подъёма targetAltitude задаёт конечную высоту; для остальных режимов она игнорируется.
+func (a *Autopilot) Engage(mode Mode, r *objects.Rocket, groundLevel int, targetAltitude float64) {
+	a.Mode = mode
+	a.altitudePID.Reset()
+	a.vUpPID.Reset()
+	a.vxPID.Reset()
+	a.burning = false
+
+	switch mode {
+	case ModeAltitudeHold:
+		a.TargetAltitude = Altitude(r, groundLevel)
+		a.TargetVx = 0
+	case ModeVelocityHold:
+		a.TargetVUp = -r.Vy
+		a.TargetVx = r.Vx
+	case ModeAscent:
+		a.TargetAltitude = targetAltitude
+	case ModeLand:
+		a.TargetVx = 0
+	}
+	if a.SelectStage && (mode == ModeAscent || mode == ModeLand) {
+		selectStage(r, groundLevel)
+	}
+	if mode == ModeLand {
+		// Место посадки ищется под спрайт ступени, на которой ракета сядет
+		a.siteX = landingSite(r)
+	}
+}
+
+// Disengage выключает автопилот, оставляя текущую тягу
+func (a *Autopilot) Disengage() {
+	a.Mode = ModeOff
+}
+
+// Update выставляет тягу ракеты для текущего режима
+func (a *Autopilot) Update(r *objects.Rocket, dt float64, groundLevel int) {
+	if a.Mode == ModeOff {
+		return
+	}
+	altitude := Altitude(r, groundLevel)
+	vUp := -r.Vy
+
+	switch a.Mode {
+	case ModeAltitudeHold:
+		targetVUp := clamp(a.altitudePID.Update(a.TargetAltitude-altitude, dt), -maxClimbRate, maxClimbRate)
+		setVerticalAccel(r, a.vUpPID.Update(targetVUp-vUp, dt), groundLevel)
+		setHorizontalAccel(r, a.vxPID.Update(a.TargetVx-r.Vx, dt))
+
+	case ModeVelocityHold:
+		setVerticalAccel(r, a.vUpPID.Update(a.TargetVUp-vUp, dt), groundLevel)
+		setHorizontalAccel(r, a.vxPID.Update(a.TargetVx-r.Vx, dt))
+
+	case ModeAscent:
+		a.updateAscent(r, dt, groundLevel, altitude, vUp)
+
+	case ModeLand:
+		a.updateLanding(r, dt, groundLevel)
+	}
+}
+
+// updateAscent разгоняет ракету вверх на полной тяге, постепенно набирая горизонтальную скорость,
+// и переходит к удержанию высоты, когда по инерции ракета дойдёт до цели
+func (a *Autopilot) updateAscent(r *objects.Rocket, dt float64, groundLevel int, altitude, vUp float64) {
+	gravity := physics.CalculateGravity(altitude)
+	apex := altitude
+	if vUp > 0 {
+		apex += vUp * vUp / (2 * gravity)
+	}
+	if apex >= a.TargetAltitude {
+		target := a.TargetAltitude
+		a.Engage(ModeAltitudeHold, r, groundLevel, 0)
+		a.TargetAltitude = target
+		return
+	}
+
+	// Гравитационный разворот: горизонтальная скорость растёт по мере подъёма
+	progress := clamp(altitude/a.TargetAltitude, 0, 1)
+	a.TargetVx = gravityTurnSpeed * progress
+	setVerticalAccel(r, fullThrustAccel, groundLevel)
+	setHorizontalAccel(r, a.vxPID.Update(a.TargetVx-r.Vx, dt))
+}
+
+// updateLanding падает свободно, пока тормозной путь меньше высоты, а затем
+// тормозит так, чтобы коснуться поверхности со скоростью touchdownSpeed.
+// Если под ракетой нет ровного места, она тормозит до высоты siteHoverHeight
+// и садится, только дойдя до места посадки.
+func (a *Autopilot) updateLanding(r *objects.Rocket, dt float64, groundLevel int) {
+	height := physics.HeightAboveTerrain(r)
+	descent := r.Vy
+	altitude := Altitude(r, groundLevel)
+	gravity := physics.CalculateGravity(altitude)
+	maxDecel := physics.VerticalThrustGain(r, r.ActiveStage)*objects.RocketStages[r.ActiveStage].MaxThrustY - gravity
+
+	x, _ := r.Position()
+	offset := float64(a.siteX) - x
+	a.TargetVx = clamp(offset*siteApproachGain, -maxDriftSpeed, maxDriftSpeed)
+	setHorizontalAccel(r, a.vxPID.Update(a.TargetVx-r.Vx, dt))
+
+	if height < landedHeight && math.Abs(descent) < landedSpeed {
+		r.ThrustY = 0
+		r.ThrustX = 0
+		a.Mode = ModeOff
+		return
+	}
+
+	// Пока место посадки в стороне, тормозим не до поверхности, а до высоты подхода
+	onSite := math.Abs(offset) <= siteTolerance
+	if !onSite {
+		height -= siteHoverHeight
+	}
+	if !a.burning && descent > 0 && maxDecel > 0 {
+		stopDistance := descent * descent / (2 * maxDecel * burnMargin)
+		a.burning = height <= stopDistance+burnBuffer
+	}
+	if !a.burning {
+		// Свободное падение до начала прожига
+		setVerticalAccel(r, -gravity, groundLevel)
+		return
+	}
+
+	// Требуемое торможение, чтобы погасить скорость до touchdownSpeed на оставшейся высоте
+	decel := 0.0
+	switch {
+	case descent > touchdownSpeed && height > 0:
+		decel = (descent*descent - touchdownSpeed*touchdownSpeed) / (2 * math.Max(height, 0.5))
+	case onSite:
+		decel = a.vUpPID.Update(descent-touchdownSpeed, dt)
+	default:
+		// Держимся на высоте подхода: опускаемся к ней сверху и поднимаемся снизу
+		decel = a.vUpPID.Update(descent-clamp(height, -touchdownSpeed, touchdownSpeed), dt)
+	}
+	setVerticalAccel(r, decel, groundLevel)
+}
+
+// landingSite возвращает левую колонку ближайшего к ракете места, где опора встанет на рельеф
+// с допустимым перепадом даже при промахе на колонку. Если такого места нет, возвращает колонку ракеты.
+func landingSite(r *objects.Rocket) int {
+	sprite := r.GetRocketSprite()
+	flat := func(x int) bool {
+		for dx := -1; dx <= 1; dx++ {
+			if physics.FootprintSlope(x+dx, sprite, &objects.Ground) > physics.MaxLandingSlope {
+				return false
+			}
+		}
+		return true
+	}
+	for d := 0; d <= siteSearchRange; d++ {
+		if flat(r.X + d) {
+			return r.X + d
+		}
+		if flat(r.X - d) {
+			return r.X - d
+		}
+	}
+	return r.X
+}
+
+// Altitude возвращает точную высоту ракеты над уровнем groundLevel с учётом дробного смещения
+func Altitude(r *objects.Rocket, groundLevel int) float64 {
+	_, y := r.Position()
+	return float64(groundLevel) - y
+}
+
+// setVerticalAccel подбирает ThrustY так, чтобы чистое ускорение вверх было равно accUp
+func setVerticalAccel(r *objects.Rocket, accUp float64, groundLevel int) {
+	gain := physics.VerticalThrustGain(r, r.ActiveStage)
+	if gain <= 0 {
+		r.ThrustY = 0
+		return
+	}
+	gravity := physics.CalculateGravity(Altitude(r, groundLevel))
+	r.ThrustY = clamp((accUp+gravity)/gain, 0, objects.RocketStages[r.ActiveStage].MaxThrustY)
+}
+
+// setHorizontalAccel подбирает ThrustX так, чтобы горизонтальное ускорение было равно acc
+func setHorizontalAccel(r *objects.Rocket, acc float64) {
+	gain := physics.HorizontalThrustGain(r, r.ActiveStage)
+	if gain <= 0 {
+		r.ThrustX = 0
+		return
+	}
+	maxThrust := objects.RocketStages[r.ActiveStage].MaxThrustX
+	r.ThrustX = clamp(acc/gain, -maxThrust, maxThrust)
+}
+
+// selectStage переключает ракету на ступень с наибольшим запасом вертикального ускорения
+func selectStage(r *objects.Rocket, groundLevel int) {
+	gravity := physics.CalculateGravity(Altitude(r, groundLevel))
+	best, bestAccel := r.ActiveStage, math.Inf(-1)
+	for i, stage := range objects.RocketStages {
+		accel := physics.VerticalThrustGain(r, i)*stage.MaxThrustY - gravity
+		if accel > bestAccel {
+			best, bestAccel = i, accel
+		}
+	}
+	r.ActiveStage = best
+}
+
+// ParseMode возвращает режим по имени, используемому в параметрах командной строки
+func ParseMode(name string) (Mode, error) {
+	switch name {
+	case "off", "":
+		return ModeOff, nil
+	case "hold":
+		return ModeAltitudeHold, nil
+	case "velocity":
+		return ModeVelocityHold, nil
+	case "ascent":
+		return ModeAscent, nil
+	case "land":
+		return ModeLand, nil
+	}
+	return ModeOff, fmt.Errorf("unknown autopilot mode %q", name)
+}
@@ -0,0 +1,103 @@
+package autopilot
+
+import (
+	"testing"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+)
+
+// canBrake проверяет, превышает ли тяга ступени stage тяжесть у поверхности текущего тела
+func canBrake(stage int) bool {
+	return objects.RocketStages[stage].MaxThrustY*physics.VerticalThrustGain(&objects.Rocket{}, stage) > physics.CurrentBody.Gravity(0)
+}
+
+// slopedColumn возвращает колонку рядом со стартом, над которой опора ракеты на ступени stage
+// встала бы на перепад больше допустимого
+func slopedColumn(t *testing.T, stage int) int {
+	t.Helper()
+	sprite := (&objects.Rocket{ActiveStage: stage}).GetRocketSprite()
+	for x := objects.Ground.LaunchX + objects.Ground.PadWidth; x < objects.Ground.LaunchX+objects.Ground.PadSpacing; x++ {
+		if physics.FootprintSlope(x, sprite, &objects.Ground) > physics.MaxLandingSlope {
+			return x
+		}
+	}
+	t.Fatal("no sloped terrain near the launch pad")
+	return 0
+}
+
+// land сажает автопилотом ракету r и возвращает исход касания по правилам игры,
+// наибольшую скорость касания и признак того, что автопилот закончил посадку
+func land(ap *Autopilot, r *objects.Rocket) (landing physics.Landing, touchdown float64, landed bool) {
+	const dt = 1.0 / 30
+	ap.Engage(ModeLand, r, objects.GroundLevel, 0)
+	for i := 0; i < 6000 && ap.Mode != ModeOff; i++ {
+		ap.Update(r, dt, objects.GroundLevel)
+		physics.UpdateRocket(r, dt, objects.GroundLevel, 0)
+		if r.ImpactSpeed > 0 || r.TerrainCollision {
+			touchdown = max(touchdown, r.ImpactSpeed)
+			landing = physics.JudgeLanding(r.ImpactSpeed, r.TerrainCollision, r.GroundSlope, r.Hull)
+			r.DamageHull(landing.Damage)
+			if landing.Crashed {
+				return landing, touchdown, false
+			}
+		}
+	}
+	return landing, touchdown, ap.Mode == ModeOff
+}
+
+func TestLandOnEveryStage(t *testing.T) {
+	defer func(body *physics.Body) { physics.CurrentBody = body }(physics.CurrentBody)
+	objects.InitTerrain(1)
+	for _, body := range physics.Bodies {
+		physics.CurrentBody = body
+		for stage := range objects.RocketStages {
+			for _, site := range []struct {
+				name string
+				x    int
+			}{{"pad", objects.Ground.LaunchX - 3}, {"slope", slopedColumn(t, stage)}} {
+				for _, height := range []float64{30, 300, 1500} {
+					r := &objects.Rocket{Fuel: 10000, ActiveStage: stage, Vx: 3, Vy: 5}
+					r.ResetDamage()
+					r.SetPosition(float64(site.x), float64(objects.Ground.SurfaceAt(site.x)-len(r.GetRocketSprite()))-height)
+
+					// Ступень, которая может затормозить, остаётся включённой;
+					// со слабой автопилот сам переходит на другую
+					ap := New()
+					ap.SelectStage = !canBrake(stage)
+					landing, touchdown, landed := land(ap, r)
+
+					name := body.Name + "/" + objects.RocketStages[stage].Name + "/" + site.name
+					switch {
+					case ap.SelectStage && !canBrake(r.ActiveStage):
+						t.Errorf("%s from %.0f: switched to stage %d that cannot brake", name, height, r.ActiveStage)
+					case !ap.SelectStage && r.ActiveStage != stage:
+						t.Errorf("%s from %.0f: stage switched to %d", name, height, r.ActiveStage)
+					case landing.Crashed:
+						t.Errorf("%s from %.0f: %s at %.2f", name, height, landing.Cause, touchdown)
+					case !landed:
+						t.Errorf("%s from %.0f: not landed, height %.2f, vy %.2f", name, height, physics.HeightAboveTerrain(r), r.Vy)
+					case touchdown >= physics.SafeLandingSpeed:
+						t.Errorf("%s from %.0f: touchdown at %.2f, want below %.0f", name, height, touchdown, physics.SafeLandingSpeed)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestEngageSelectsStage(t *testing.T) {
+	objects.InitTerrain(1)
+	x := objects.Ground.LaunchX
+	r := &objects.Rocket{Fuel: 10000}
+	r.ResetDamage()
+	r.SetPosition(float64(x), float64(objects.Ground.SurfaceAt(x))-300)
+	selectStage(r, objects.GroundLevel)
+	best := r.ActiveStage
+	r.ActiveStage = (best + 1) % len(objects.RocketStages)
+
+	New().Engage(ModeLand, r, objects.GroundLevel, 0)
+	if r.ActiveStage != best {
+		t.Errorf("Engage(ModeLand) left stage %d, want %d", r.ActiveStage, best)
+	}
+}
@@ -0,0 +1,46 @@
+package autopilot
+
+// PID - классический ПИД-регулятор с ограничением интегральной составляющей
+type PID struct {
+	Kp, Ki, Kd    float64
+	IntegralLimit float64 // ограничение интеграла по модулю (0 - без ограничения)
+
+	integral float64
+	prevErr  float64
+	primed   bool // предыдущая ошибка уже известна
+}
+
+// Update возвращает управляющее воздействие по ошибке err за шаг dt
+func (p *PID) Update(err, dt float64) float64 {
+	if dt <= 0 {
+		return p.Kp * err
+	}
+	p.integral += err * dt
+	if p.IntegralLimit > 0 {
+		p.integral = clamp(p.integral, -p.IntegralLimit, p.IntegralLimit)
+	}
+	derivative := 0.0
+	if p.primed {
+		derivative = (err - p.prevErr) / dt
+	}
+	p.prevErr = err
+	p.primed = true
+	return p.Kp*err + p.Ki*p.integral + p.Kd*derivative
+}
+
+// Reset сбрасывает накопленное состояние регулятора
+func (p *PID) Reset() {
+	p.integral = 0
+	p.prevErr = 0
+	p.primed = false
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if v < lo {
+		return lo
+	}
+	if v > hi {
+		return hi
+	}
+	return v
+}
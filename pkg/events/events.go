@@ -0,0 +1,59 @@
+// Package events - шина игровых событий. Физика, миссии и основной цикл публикуют события,
+// а подписчики (HUD, звук, телеметрия, достижения, повторы) обрабатывают их,
+// не зная, кто и где событие обнаружил.
+package events
+
+// Event - игровое событие. Событием может быть любой тип с текстовым описанием,
+// поэтому миссии и новые подсистемы могут заводить собственные события.
+type Event interface {
+	String() string
+}
+
+// maxDispatchRounds ограничивает цепочки событий, которые подписчики публикуют в ответ на события
+const maxDispatchRounds = 16
+
+// Bus копит опубликованные события и раздаёт их подписчикам в Dispatch.
+// Очередь позволяет публиковать события посреди шага физики, а обрабатывать - в конце кадра.
+type Bus struct {
+	handlers []func(Event)
+	queue    []Event
+}
+
+// NewBus создаёт пустую шину
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe подписывает handler на события типа E
+func Subscribe[E Event](b *Bus, handler func(E)) {
+	b.handlers = append(b.handlers, func(e Event) {
+		if ev, ok := e.(E); ok {
+			handler(ev)
+		}
+	})
+}
+
+// SubscribeAll подписывает handler на все события
+func (b *Bus) SubscribeAll(handler func(Event)) {
+	b.handlers = append(b.handlers, handler)
+}
+
+// Publish ставит событие в очередь
+func (b *Bus) Publish(e Event) {
+	b.queue = append(b.queue, e)
+}
+
+// Dispatch раздаёт накопленные события подписчикам в порядке публикации.
+// События, опубликованные подписчиками, раздаются в том же вызове.
+func (b *Bus) Dispatch() {
+	for round := 0; round < maxDispatchRounds && len(b.queue) > 0; round++ {
+		queue := b.queue
+		b.queue = nil
+		for _, e := range queue {
+			for _, handle := range b.handlers {
+				handle(e)
+			}
+		}
+	}
+	b.queue = nil
+}
@@ -0,0 +1,101 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// Liftoff - ракета оторвалась от поверхности
+type Liftoff struct {
+	Time float64
+}
+
+func (e Liftoff) String() string {
+	return fmt.Sprintf("liftoff at %.2fs", e.Time)
+}
+
+// Landed - ракета мягко коснулась поверхности
+type Landed struct {
+	Time  float64
+	Speed float64 // скорость касания
+	OnPad bool    // посадка на площадку
+}
+
+func (e Landed) String() string {
+	where := "terrain"
+	if e.OnPad {
+		where = "pad"
+	}
+	return fmt.Sprintf("landed on %s at %.2fs, speed %.2f", where, e.Time, e.Speed)
+}
+
+// StageChanged - переключена ступень ракеты
+type StageChanged struct {
+	Time     float64
+	From, To int
+}
+
+func (e StageChanged) String() string {
+	return fmt.Sprintf("stage %s -> %s at %.2fs", objects.RocketStages[e.From].Name, objects.RocketStages[e.To].Name, e.Time)
+}
+
+// KarmanCrossed - ракета пересекла границу космоса
+type KarmanCrossed struct {
+	Time      float64
+	Ascending bool // вылет в космос; false - возвращение в атмосферу
+}
+
+func (e KarmanCrossed) String() string {
+	if e.Ascending {
+		return fmt.Sprintf("crossed the Karman line at %.2fs", e.Time)
+	}
+	return fmt.Sprintf("re-entered the atmosphere at %.2fs", e.Time)
+}
+
+// AtmosphereLayer - ракета перешла в другой слой атмосферы
+type AtmosphereLayer struct {
+	Time      float64
+	Name      string // название нового слоя
+	Ascending bool
+}
+
+func (e AtmosphereLayer) String() string {
+	return fmt.Sprintf("entered the %s at %.2fs", e.Name, e.Time)
+}
+
+// LowFuel - топлива осталось меньше порога правила предупреждения о нехватке топлива
+type LowFuel struct {
+	Time float64
+	Fuel float64
+}
+
+func (e LowFuel) String() string {
+	return fmt.Sprintf("low fuel (%.1f) at %.2fs", e.Fuel, e.Time)
+}
+
+// CosmicSpeed - скорость падения ракеты превысила порог "космической" или опустилась ниже него
+type CosmicSpeed struct {
+	Time    float64
+	Speed   float64
+	Reached bool // порог превышен; false - скорость снова ниже порога
+}
+
+func (e CosmicSpeed) String() string {
+	if e.Reached {
+		return fmt.Sprintf("cosmic speed %.1f at %.2fs", e.Speed, e.Time)
+	}
+	return fmt.Sprintf("cosmic speed lost at %.2fs", e.Time)
+}
+
+// Crashed - ракета разбилась
+type Crashed struct {
+	Time     float64
+	Cause    objects.CrashCause
+	Speed    float64 // скорость удара
+	Altitude float64
+}
+
+func (e Crashed) String() string {
+	return fmt.Sprintf("crashed at %.2fs: %s (speed %.2f)", e.Time, e.Cause, e.Speed)
+}
@@ -0,0 +1,110 @@
+package ghost
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Параметры записи и сравнения
+const (
+	DefaultInterval = 0.1 // секунды между образцами записи
+	matchWindow     = 5.0 // на сколько секунд вперёд по записи ищется точка, ближайшая к ракете
+)
+
+// Sample - положение ракеты в момент T полёта
+type Sample struct {
+	T     float64 `json:"t"`
+	X     float64 `json:"x"`
+	Y     float64 `json:"y"`
+	Stage int     `json:"stage"`
+}
+
+// Track - записанный полёт по миссии
+type Track struct {
+	Mission  string    `json:"mission"`
+	Time     float64   `json:"time"` // время прохождения миссии
+	Recorded time.Time `json:"recorded"`
+	Samples  []Sample  `json:"samples"`
+}
+
+// At возвращает положение призрака в момент t, интерполируя между образцами.
+// До начала и после конца записи призрак стоит в крайней точке.
+func (tr *Track) At(t float64) Sample {
+	samples := tr.Samples
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].T > t })
+	switch {
+	case i == 0:
+		return samples[0]
+	case i == len(samples):
+		return samples[len(samples)-1]
+	}
+	a, b := samples[i-1], samples[i]
+	k := (t - a.T) / (b.T - a.T)
+	return Sample{T: t, X: a.X + (b.X-a.X)*k, Y: a.Y + (b.Y-a.Y)*k, Stage: a.Stage}
+}
+
+// Delta сравнивает ракету в точке (x, y) в момент t с записью: возвращает, на сколько секунд
+// ракета отстаёт от призрака (отрицательное - опережает), и индекс ближайшего образца.
+// Ближайший образец ищется только вперёд от from, в пределах matchWindow секунд записи,
+// чтобы на спуске ракета не сравнивалась с той же высотой на подъёме и наоборот.
+func (tr *Track) Delta(t, x, y float64, from int) (float64, int) {
+	from = max(0, min(from, len(tr.Samples)-1))
+	best, bestDist := from, math.Inf(1)
+	limit := tr.Samples[from].T + matchWindow
+	for i := from; i < len(tr.Samples) && tr.Samples[i].T <= limit; i++ {
+		s := tr.Samples[i]
+		if d := math.Hypot(s.X-x, s.Y-y); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return t - tr.Samples[best].T, best
+}
+
+// Recorder записывает полёт. Полёт засчитывается, если ракета набрала высоту Goal
+// и после этого благополучно приземлилась.
+type Recorder struct {
+	Interval float64
+	Goal     float64 // высота, которую нужно набрать, в игровых единицах
+
+	track   Track
+	next    float64
+	reached bool
+	done    bool
+}
+
+// NewRecorder начинает запись полёта по миссии mission
+func NewRecorder(mission string, goal float64) *Recorder {
+	return &Recorder{Interval: DefaultInterval, Goal: goal, track: Track{Mission: mission}}
+}
+
+// Record добавляет положение ракеты (x, y) на высоте altitude в момент t
+func (r *Recorder) Record(t, x, y, altitude float64, stage int) {
+	if r.done {
+		return
+	}
+	if altitude >= r.Goal {
+		r.reached = true
+	}
+	if t >= r.next {
+		r.track.Samples = append(r.track.Samples, Sample{T: t, X: x, Y: y, Stage: stage})
+		r.next = t + r.Interval
+	}
+}
+
+// Reached возвращает true, если ракета уже набрала нужную высоту
+func (r *Recorder) Reached() bool {
+	return r.reached
+}
+
+// Finish завершает запись посадкой в момент t и возвращает запись, если полёт засчитан
+func (r *Recorder) Finish(t float64, x, y float64, stage int) (*Track, bool) {
+	if r.done || !r.reached {
+		return nil, false
+	}
+	r.done = true
+	r.track.Samples = append(r.track.Samples, Sample{T: t, X: x, Y: y, Stage: stage})
+	r.track.Time = t
+	r.track.Recorded = time.Now()
+	return &r.track, true
+}
@@ -0,0 +1,69 @@
+package ghost
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+// DefaultDir возвращает каталог лучших полётов в каталоге настроек пользователя
+func DefaultDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "rocket-in-console", "ghosts")
+}
+
+// Load читает лучший полёт по миссии mission из каталога dir.
+// Если полётов по миссии ещё не было, возвращает nil без ошибки.
+func Load(dir, mission string) (*Track, error) {
+	data, err := os.ReadFile(trackPath(dir, mission))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var track Track
+	if err := json.Unmarshal(data, &track); err != nil {
+		return nil, err
+	}
+	if len(track.Samples) == 0 {
+		return nil, nil
+	}
+	return &track, nil
+}
+
+// Save записывает полёт в каталог dir, заменяя прежний лучший полёт по той же миссии
+func Save(dir string, track *Track) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(track)
+	if err != nil {
+		return err
+	}
+	// Пишем во временный файл и переименовываем, чтобы не потерять рекорд при сбое записи
+	path := trackPath(dir, track.Mission)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// trackPath возвращает имя файла миссии, заменяя символы, недопустимые в именах файлов
+func trackPath(dir, mission string) string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '.' {
+			return r
+		}
+		return '_'
+	}, mission)
+	return filepath.Join(dir, name+".json")
+}
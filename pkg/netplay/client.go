@@ -0,0 +1,128 @@
+package netplay
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// Client - подключение игрока к серверу. Снимки принимаются в фоне; Poll отдаёт самый свежий.
+// Send и Poll вызываются из одной горутины игрового цикла.
+type Client struct {
+	Welcome Welcome
+
+	conn    net.Conn
+	enc     *json.Encoder
+	seq     uint32
+	pending []Input // отправленный ввод, который сервер ещё не применил
+
+	mu     sync.Mutex
+	latest *Snapshot
+	err    error
+}
+
+// Dial подключается к серверу addr под именем name
+func Dial(addr, name string) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", addr, handshakeTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewClient(conn, name)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewClient представляется серверу по уже открытому соединению conn и ждёт приветствия
+func NewClient(conn net.Conn, name string) (*Client, error) {
+	enc := json.NewEncoder(conn)
+	dec := json.NewDecoder(conn)
+	if err := enc.Encode(message{Hello: &Hello{Version: ProtocolVersion, Name: name}}); err != nil {
+		return nil, err
+	}
+	var reply message
+	conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	if err := dec.Decode(&reply); err != nil {
+		return nil, err
+	}
+	conn.SetReadDeadline(time.Time{})
+	switch {
+	case reply.Error != "":
+		return nil, errors.New(reply.Error)
+	case reply.Welcome == nil:
+		return nil, errors.New("server did not welcome the player")
+	}
+
+	c := &Client{Welcome: *reply.Welcome, conn: conn, enc: enc}
+	go c.read(dec)
+	return c, nil
+}
+
+// ID возвращает номер игрока, выданный сервером
+func (c *Client) ID() int {
+	return c.Welcome.ID
+}
+
+// read принимает снимки, пока соединение не закроется; промежуточные снимки заменяются свежими
+func (c *Client) read(dec *json.Decoder) {
+	for {
+		var m message
+		err := dec.Decode(&m)
+		if err == nil && m.Error != "" {
+			err = errors.New(m.Error)
+		}
+		c.mu.Lock()
+		if err != nil {
+			c.err = err
+			c.mu.Unlock()
+			return
+		}
+		if m.Snapshot != nil {
+			c.latest = m.Snapshot
+		}
+		c.mu.Unlock()
+	}
+}
+
+// Send нумерует и отправляет управление на следующий шаг сервера
+func (c *Client) Send(in Input) (Input, error) {
+	c.seq++
+	in.Seq = c.seq
+	c.pending = append(c.pending, in)
+	return in, c.enc.Encode(message{Input: &in})
+}
+
+// Poll возвращает снимок, пришедший после предыдущего вызова, или nil, если нового нет,
+// и забывает ввод, который сервер применил к этому снимку. Ошибка означает, что соединение потеряно.
+func (c *Client) Poll() (*Snapshot, error) {
+	c.mu.Lock()
+	snapshot, err := c.latest, c.err
+	c.latest = nil
+	c.mu.Unlock()
+	if snapshot == nil {
+		return nil, err
+	}
+	if own := snapshot.Player(c.ID()); own != nil {
+		acked := 0
+		for acked < len(c.pending) && c.pending[acked].Seq <= own.Ack {
+			acked++
+		}
+		c.pending = c.pending[acked:]
+	}
+	return snapshot, nil
+}
+
+// Pending возвращает отправленный ввод, которого нет в последнем снимке: для сверки
+// предсказания клиент повторяет его поверх состояния из снимка
+func (c *Client) Pending() []Input {
+	return c.pending
+}
+
+// Close отключается от сервера
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
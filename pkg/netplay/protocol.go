@@ -0,0 +1,102 @@
+// Package netplay - сетевая игра по TCP. Сервер ведёт авторитетную симуляцию с фиксированным шагом,
+// клиенты присылают ввод с номерами, а сервер рассылает снимки состояния всех ракет и препятствий.
+// Клиент предсказывает свою ракету, не дожидаясь сервера, и сверяет предсказание с каждым снимком,
+// повторяя ввод, который сервер ещё не подтвердил. Пакет не знает об игровых правилах:
+// их реализует Simulation, а сервер и клиенты работают поверх любых net.Conn и net.Listener.
+package netplay
+
+import (
+	"time"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// ProtocolVersion - версия протокола; сервер отказывает клиентам другой версии
+const ProtocolVersion = 2
+
+// Параметры протокола
+const (
+	DefaultTickRate  = 30              // шагов симуляции сервера в секунду
+	handshakeTimeout = 5 * time.Second // сколько ждать приветствия при подключении
+	maxQueuedInputs  = 4               // сколько неприменённых команд игрока хранит сервер
+	snapshotQueue    = 4               // сколько снимков ждут отправки медленному клиенту
+)
+
+// Hello - первое сообщение клиента
+type Hello struct {
+	Version int    `json:"version"`
+	Name    string `json:"name"`
+}
+
+// Welcome - ответ сервера на Hello: номер игрока и всё, что нужно, чтобы построить тот же мир
+type Welcome struct {
+	ID             int     `json:"id"`
+	Tick           float64 `json:"tick"` // шаг симуляции в секундах; клиент предсказывает с тем же шагом
+	Time           float64 `json:"time"` // время мира на сервере
+	Seed           int64   `json:"seed"`
+	Body           string  `json:"body"`
+	Weather        string  `json:"weather"`
+	Integrator     string  `json:"integrator"`
+	Hour           float64 `json:"hour"`
+	DayLength      float64 `json:"day_length"`
+	AscentAltitude float64 `json:"ascent_altitude"`
+	Race           string  `json:"race"` // цель гонки
+}
+
+// Input - управление игрока на один шаг симуляции
+type Input struct {
+	Seq     uint32 `json:"seq"`
+	Up      bool   `json:"up,omitempty"`
+	Down    bool   `json:"down,omitempty"`
+	Left    bool   `json:"left,omitempty"`
+	Right   bool   `json:"right,omitempty"`
+	Stage   bool   `json:"stage,omitempty"`
+	Respawn bool   `json:"respawn,omitempty"`
+}
+
+// PlayerState - состояние игрока в снимке
+type PlayerState struct {
+	ID     int                  `json:"id"`
+	Name   string               `json:"name"`
+	Rocket objects.Rocket       `json:"rocket"`
+	Crash  *objects.CrashReport `json:"crash,omitempty"` // nil - ракета цела
+	Ack    uint32               `json:"ack"`             // номер последней применённой команды игрока
+}
+
+// EntityState - видимая сущность мира в снимке: всё, что нужно клиенту, чтобы её нарисовать
+type EntityState struct {
+	Position objects.Position `json:"position"`
+	Velocity objects.Velocity `json:"velocity"`
+	Sprite   objects.Sprite   `json:"sprite"`
+	Age      float64          `json:"age"`
+}
+
+// Snapshot - состояние мира после шага сервера
+type Snapshot struct {
+	Tick     uint64        `json:"tick"`
+	Time     float64       `json:"time"`
+	Players  []PlayerState `json:"players"`
+	Entities []EntityState `json:"entities,omitempty"` // препятствия; сталкивается с ними только сервер
+	RaceTime float64       `json:"race_time"`
+	Winner   int           `json:"winner"` // ID победителя гонки (0 - гонка идёт)
+}
+
+// Player возвращает состояние игрока id или nil, если его нет в снимке
+func (s *Snapshot) Player(id int) *PlayerState {
+	for i := range s.Players {
+		if s.Players[i].ID == id {
+			return &s.Players[i]
+		}
+	}
+	return nil
+}
+
+// message - сообщение протокола; сообщения передаются JSON по одному в строке,
+// и в каждом заполнено ровно одно поле
+type message struct {
+	Hello    *Hello    `json:"hello,omitempty"`
+	Welcome  *Welcome  `json:"welcome,omitempty"`
+	Input    *Input    `json:"input,omitempty"`
+	Snapshot *Snapshot `json:"snapshot,omitempty"`
+	Error    string    `json:"error,omitempty"`
+}
@@ -0,0 +1,260 @@
+package netplay
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Simulation - игровой мир, который ведёт сервер. Сервер вызывает методы под своей блокировкой,
+// поэтому реализации не нужно заботиться о конкурентном доступе.
+type Simulation interface {
+	// Join добавляет игрока и возвращает его приветствие (ID и параметры мира)
+	Join(name string) (Welcome, error)
+	// Leave убирает игрока
+	Leave(id int)
+	// Control применяет управление игрока перед шагом dt
+	Control(id int, in Input, dt float64)
+	// Step продвигает мир на dt секунд
+	Step(dt float64)
+	// Snapshot возвращает состояние мира; подтверждения ввода заполняет сервер
+	Snapshot() Snapshot
+}
+
+// Server ведёт симуляцию с фиксированным шагом и обменивается сообщениями с клиентами
+type Server struct {
+	Interval time.Duration // шаг симуляции
+
+	sim  Simulation
+	mu   sync.Mutex
+	tick uint64
+
+	peers     map[int]*peer
+	listeners []net.Listener
+	closed    bool
+	done      chan struct{}
+}
+
+// peer - подключённый игрок
+type peer struct {
+	conn   net.Conn
+	inputs []Input // ввод, ещё не применённый к симуляции
+	last   Input   // последняя применённая команда: её клавиши держатся, пока не придёт следующая
+	ack    uint32
+	out    chan Snapshot
+}
+
+// NewServer создаёт сервер симуляции sim с шагом DefaultTickRate
+func NewServer(sim Simulation) *Server {
+	return &Server{
+		Interval: time.Second / DefaultTickRate,
+		sim:      sim,
+		peers:    make(map[int]*peer),
+		done:     make(chan struct{}),
+	}
+}
+
+// Run принимает подключения на l и шагает симуляцию по таймеру, пока сервер не закрыт
+func (s *Server) Run(l net.Listener) error {
+	go func() {
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.Tick()
+			case <-s.done:
+				return
+			}
+		}
+	}()
+	return s.Serve(l)
+}
+
+// Serve принимает подключения на l, не шагая симуляцию: шаги делает Run или сам вызывающий через Tick
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return net.ErrClosed
+	}
+	s.listeners = append(s.listeners, l)
+	s.mu.Unlock()
+
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if s.isClosed() {
+				return nil
+			}
+			return err
+		}
+		go s.handle(conn)
+	}
+}
+
+// Tick применяет по одной команде каждого игрока, делает шаг симуляции и рассылает снимок.
+// Если команда игрока опоздала к шагу, повторяются клавиши его прошлой команды без разовых нажатий,
+// чтобы управление не застывало; подтверждение при этом не меняется.
+func (s *Server) Tick() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	dt := s.Interval.Seconds()
+
+	ids := make([]int, 0, len(s.peers))
+	for id := range s.peers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		p := s.peers[id]
+		if len(p.inputs) == 0 {
+			held := p.last
+			held.Stage, held.Respawn = false, false
+			s.sim.Control(id, held, dt)
+			continue
+		}
+		in := p.inputs[0]
+		p.inputs = p.inputs[1:]
+		s.sim.Control(id, in, dt)
+		p.last = in
+		p.ack = in.Seq
+	}
+	s.sim.Step(dt)
+
+	snapshot := s.sim.Snapshot()
+	s.tick++
+	snapshot.Tick = s.tick
+	for i := range snapshot.Players {
+		if p := s.peers[snapshot.Players[i].ID]; p != nil {
+			snapshot.Players[i].Ack = p.ack
+		}
+	}
+	for _, p := range s.peers {
+		// Медленный клиент пропускает снимки, а не задерживает остальных
+		select {
+		case p.out <- snapshot:
+		default:
+		}
+	}
+}
+
+// Close перестаёт принимать подключения и отключает всех игроков
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	close(s.done)
+	var errs []error
+	for _, l := range s.listeners {
+		errs = append(errs, l.Close())
+	}
+	for _, p := range s.peers {
+		p.conn.Close()
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Server) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closed
+}
+
+// handle ведёт одно подключение: приветствие, затем приём ввода, пока клиент не отключится
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	enc := json.NewEncoder(conn)
+	dec := json.NewDecoder(conn)
+
+	var hello message
+	conn.SetReadDeadline(time.Now().Add(handshakeTimeout))
+	if err := dec.Decode(&hello); err != nil || hello.Hello == nil {
+		return
+	}
+	conn.SetReadDeadline(time.Time{})
+	if hello.Hello.Version != ProtocolVersion {
+		enc.Encode(message{Error: fmt.Sprintf("protocol version %d is not supported, server speaks %d", hello.Hello.Version, ProtocolVersion)})
+		return
+	}
+
+	p, welcome, err := s.join(conn, hello.Hello.Name)
+	if err != nil {
+		enc.Encode(message{Error: err.Error()})
+		return
+	}
+	defer s.leave(welcome.ID)
+	if err := enc.Encode(message{Welcome: &welcome}); err != nil {
+		return
+	}
+
+	go func() {
+		for snapshot := range p.out {
+			if err := enc.Encode(message{Snapshot: &snapshot}); err != nil {
+				// Чтение ниже прервётся, и игрок покинет игру
+				conn.Close()
+				return
+			}
+		}
+	}()
+
+	for {
+		var m message
+		if err := dec.Decode(&m); err != nil {
+			return
+		}
+		if m.Input != nil {
+			s.queue(p, *m.Input)
+		}
+	}
+}
+
+// join добавляет игрока в симуляцию
+func (s *Server) join(conn net.Conn, name string) (*peer, Welcome, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, Welcome{}, errors.New("server is shutting down")
+	}
+	welcome, err := s.sim.Join(name)
+	if err != nil {
+		return nil, Welcome{}, err
+	}
+	welcome.Tick = s.Interval.Seconds()
+	p := &peer{conn: conn, out: make(chan Snapshot, snapshotQueue)}
+	s.peers[welcome.ID] = p
+	return p, welcome, nil
+}
+
+// leave убирает игрока из симуляции и останавливает отправку ему снимков
+func (s *Server) leave(id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if p, ok := s.peers[id]; ok {
+		delete(s.peers, id)
+		close(p.out)
+		s.sim.Leave(id)
+	}
+}
+
+// queue ставит команду игрока в очередь. Если клиент присылает ввод быстрее, чем сервер
+// его применяет, самые старые команды отбрасываются, чтобы задержка не росла; разовые нажатия
+// из них переносятся в следующую команду, а расхождение с предсказанием клиент исправит по снимку.
+func (s *Server) queue(p *peer, in Input) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p.inputs = append(p.inputs, in)
+	for len(p.inputs) > maxQueuedInputs {
+		dropped := p.inputs[0]
+		p.inputs = p.inputs[1:]
+		p.inputs[0].Stage = p.inputs[0].Stage || dropped.Stage
+		p.inputs[0].Respawn = p.inputs[0].Respawn || dropped.Respawn
+	}
+}
@@ -0,0 +1,208 @@
+package netplay
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// counter - симуляция, в которой у каждого игрока одно число: Up прибавляет к нему единицу, Down вычитает.
+// Число передаётся в снимке полем Fuel ракеты.
+type counter struct {
+	values map[int]int
+	stages map[int]int // сколько раз игрок переключил ступень
+	next   int
+}
+
+func newCounter() *counter {
+	return &counter{values: make(map[int]int), stages: make(map[int]int)}
+}
+
+func (c *counter) Join(name string) (Welcome, error) {
+	c.next++
+	c.values[c.next] = 0
+	return Welcome{ID: c.next}, nil
+}
+
+func (c *counter) Leave(id int) {
+	delete(c.values, id)
+}
+
+func (c *counter) Control(id int, in Input, dt float64) {
+	if in.Up {
+		c.values[id]++
+	}
+	if in.Down {
+		c.values[id]--
+	}
+	if in.Stage {
+		c.stages[id]++
+	}
+}
+
+func (c *counter) Step(dt float64) {}
+
+func (c *counter) Snapshot() Snapshot {
+	var snapshot Snapshot
+	for id := 1; id <= c.next; id++ {
+		if value, ok := c.values[id]; ok {
+			snapshot.Players = append(snapshot.Players, PlayerState{ID: id, Rocket: objects.Rocket{Fuel: float64(value)}})
+		}
+	}
+	return snapshot
+}
+
+// testServer запускает сервер симуляции sim на случайном порту; шаги делает сам тест через Tick
+func testServer(t *testing.T, sim Simulation) (*Server, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewServer(sim)
+	go s.Serve(l)
+	t.Cleanup(func() { s.Close() })
+	return s, l.Addr().String()
+}
+
+// waitFor ждёт, пока условие cond не выполнится
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// waitQueued ждёт, пока у каждого игрока в очереди сервера не окажется n команд
+func waitQueued(t *testing.T, s *Server, n int) {
+	t.Helper()
+	waitFor(t, "inputs to arrive", func() bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		for _, p := range s.peers {
+			if len(p.inputs) < n {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// waitSnapshot ждёт снимок шага tick и возвращает состояние игрока клиента c
+func waitSnapshot(t *testing.T, c *Client, tick uint64) PlayerState {
+	t.Helper()
+	var own *PlayerState
+	waitFor(t, "a snapshot", func() bool {
+		snapshot, err := c.Poll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot != nil && snapshot.Tick >= tick {
+			own = snapshot.Player(c.ID())
+		}
+		return own != nil
+	})
+	return *own
+}
+
+// predicted возвращает число игрока, предсказанное клиентом: снимок плюс неподтверждённый ввод
+func predicted(c *Client, own PlayerState) int {
+	value := int(own.Rocket.Fuel)
+	for _, in := range c.Pending() {
+		if in.Up {
+			value++
+		}
+		if in.Down {
+			value--
+		}
+	}
+	return value
+}
+
+func TestServerAcksAndReconciliation(t *testing.T) {
+	s, addr := testServer(t, newCounter())
+	a, err := Dial(addr, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := Dial(addr, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	// Шаг за шагом: каждая команда применяется на своём шаге и сразу подтверждается
+	tick := uint64(0)
+	for i := 1; i <= 10; i++ {
+		a.Send(Input{Up: true})
+		b.Send(Input{Down: i%2 == 0})
+		waitQueued(t, s, 1)
+		s.Tick()
+		tick++
+		ownA, ownB := waitSnapshot(t, a, tick), waitSnapshot(t, b, tick)
+		if ownA.Ack != uint32(i) || ownB.Ack != uint32(i) {
+			t.Fatalf("tick %d: acks %d and %d, want %d", tick, ownA.Ack, ownB.Ack, i)
+		}
+		if len(a.Pending()) != 0 || len(b.Pending()) != 0 {
+			t.Fatalf("tick %d: %d and %d inputs still pending", tick, len(a.Pending()), len(b.Pending()))
+		}
+		if ownA.Rocket.Fuel != float64(i) || ownB.Rocket.Fuel != float64(-i/2) {
+			t.Fatalf("tick %d: values %.0f and %.0f, want %d and %d", tick, ownA.Rocket.Fuel, ownB.Rocket.Fuel, i, -i/2)
+		}
+	}
+
+	// Клиент ушёл вперёд: сервер применяет по одной команде за шаг,
+	// а снимок вместе с неподтверждённым вводом даёт предсказанное клиентом значение
+	for i := 0; i < 3; i++ {
+		a.Send(Input{Up: true})
+		b.Send(Input{})
+	}
+	waitQueued(t, s, 3)
+	for left := 2; left >= 0; left-- {
+		s.Tick()
+		tick++
+		own := waitSnapshot(t, a, tick)
+		if len(a.Pending()) != left {
+			t.Fatalf("tick %d: %d inputs pending, want %d", tick, len(a.Pending()), left)
+		}
+		if got := predicted(a, own); got != 13 {
+			t.Fatalf("tick %d: reconciled value %d, want 13", tick, got)
+		}
+	}
+}
+
+func TestServerHoldsLateInput(t *testing.T) {
+	sim := newCounter()
+	s, addr := testServer(t, sim)
+	c, err := Dial(addr, "c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	sent, _ := c.Send(Input{Up: true, Stage: true})
+	waitQueued(t, s, 1)
+	s.Tick()
+	// Команда на следующий шаг опоздала: клавиша газа остаётся нажатой, разовое нажатие не повторяется
+	s.Tick()
+	own := waitSnapshot(t, c, 2)
+	if own.Ack != sent.Seq {
+		t.Errorf("ack %d after a late input, want %d", own.Ack, sent.Seq)
+	}
+	if own.Rocket.Fuel != 2 {
+		t.Errorf("value %.0f, want the held key applied on both ticks", own.Rocket.Fuel)
+	}
+	s.mu.Lock()
+	stages := sim.stages[c.ID()]
+	s.mu.Unlock()
+	if stages != 1 {
+		t.Errorf("stage toggled %d times, want once", stages)
+	}
+}
@@ -0,0 +1,148 @@
+package objects
+
+import (
+	"container/list"
+	"math/rand"
+)
+
+// ChunkSize - ширина чанка мира в колонках
+const ChunkSize = 256
+
+// Плотность декораций на один чанк по умолчанию
+const (
+	DefaultTreesPerChunk  = 5
+	DefaultCloudsPerChunk = 5
+)
+
+// sceneryCellSize - размер ячейки пространственного индекса декораций
+const sceneryCellSize = 32
+
+// Chunk хранит декорации одного участка мира шириной ChunkSize
+type Chunk struct {
+	Index  int // номер чанка; колонки [Index*ChunkSize, (Index+1)*ChunkSize)
+	Trees  []Tree
+	Clouds []Cloud
+
+	treeIDs  []int // идентификаторы деревьев в индексе мира
+	cloudIDs []int // идентификаторы облаков в индексе мира
+}
+
+// World - бесконечный мир, декорации которого генерируются лениво по чанкам.
+// Чанк полностью определяется зерном и номером, поэтому вытесненный из кэша
+// чанк при повторном запросе восстанавливается в точности таким же.
+type World struct {
+	Seed    int64
+	Terrain *Terrain
+
+	TreesPerChunk  int // плотность деревьев (0 - деревьев нет)
+	CloudsPerChunk int // плотность облаков (0 - облаков нет)
+
+	capacity int
+	chunks   map[int]*list.Element
+	lru      *list.List // от недавно использованных к давно использованным
+
+	trees  *SpatialGrid[Tree]  // индекс деревьев загруженных чанков
+	clouds *SpatialGrid[Cloud] // индекс облаков загруженных чанков
+}
+
+// Scenery - декорации текущего мира
+var Scenery *World
+
+// NewWorld создаёт мир с кэшем на capacity чанков
+func NewWorld(seed int64, terrain *Terrain, capacity int) *World {
+	return &World{
+		Seed:           seed,
+		Terrain:        terrain,
+		TreesPerChunk:  DefaultTreesPerChunk,
+		CloudsPerChunk: DefaultCloudsPerChunk,
+		capacity:       capacity,
+		chunks:         make(map[int]*list.Element),
+		lru:            list.New(),
+		trees:          NewSpatialGrid[Tree](sceneryCellSize),
+		clouds:         NewSpatialGrid[Cloud](sceneryCellSize),
+	}
+}
+
+// ChunkIndex возвращает номер чанка, которому принадлежит колонка x
+func ChunkIndex(x int) int {
+	return floorDiv(x, ChunkSize)
+}
+
+// Chunk возвращает чанк с номером index, генерируя его при необходимости
+func (w *World) Chunk(index int) *Chunk {
+	if el, ok := w.chunks[index]; ok {
+		w.lru.MoveToFront(el)
+		return el.Value.(*Chunk)
+	}
+
+	chunk := w.generate(index)
+	for _, tree := range chunk.Trees {
+		chunk.treeIDs = append(chunk.treeIDs, w.trees.Insert(SpriteRect(tree.X, tree.Y, tree.Sprite), tree))
+	}
+	for _, cloud := range chunk.Clouds {
+		chunk.cloudIDs = append(chunk.cloudIDs, w.clouds.Insert(SpriteRect(cloud.X, cloud.Y, cloud.Sprite), cloud))
+	}
+	w.chunks[index] = w.lru.PushFront(chunk)
+
+	for w.lru.Len() > w.capacity {
+		oldest := w.lru.Back()
+		w.lru.Remove(oldest)
+		w.evict(oldest.Value.(*Chunk))
+	}
+	return chunk
+}
+
+// evict убирает объекты чанка из индекса мира
+func (w *World) evict(chunk *Chunk) {
+	delete(w.chunks, chunk.Index)
+	for _, id := range chunk.treeIDs {
+		w.trees.Remove(id)
+	}
+	for _, id := range chunk.cloudIDs {
+		w.clouds.Remove(id)
+	}
+}
+
+// Loaded возвращает число чанков в кэше
+func (w *World) Loaded() int {
+	return w.lru.Len()
+}
+
+// generate детерминированно строит содержимое чанка из зерна мира и номера чанка
+func (w *World) generate(index int) *Chunk {
+	rng := rand.New(rand.NewSource(w.Seed ^ int64(index)*0x5DEECE66D))
+	x0 := index * ChunkSize
+	return &Chunk{
+		Index:  index,
+		Trees:  GenerateTrees(rng, w.Terrain, x0, ChunkSize, w.TreesPerChunk),
+		Clouds: GenerateClouds(rng, x0, ChunkSize, w.CloudsPerChunk),
+	}
+}
+
+// ensureLoaded загружает все чанки, объекты которых могут попасть в область area
+func (w *World) ensureLoaded(area Rect, margin int) {
+	from, to := ChunkIndex(area.X-margin), ChunkIndex(area.X+area.W)
+	for i := from; i <= to; i++ {
+		w.Chunk(i)
+	}
+}
+
+// TreesIn возвращает деревья, пересекающие область area
+func (w *World) TreesIn(area Rect) []Tree {
+	w.ensureLoaded(area, len(TreeSprite[0]))
+	var trees []Tree
+	w.trees.Query(area, func(tree Tree, _ Rect) {
+		trees = append(trees, tree)
+	})
+	return trees
+}
+
+// CloudsIn возвращает облака, пересекающие область area
+func (w *World) CloudsIn(area Rect) []Cloud {
+	w.ensureLoaded(area, len(CloudSprite[0]))
+	var clouds []Cloud
+	w.clouds.Query(area, func(cloud Cloud, _ Rect) {
+		clouds = append(clouds, cloud)
+	})
+	return clouds
+}
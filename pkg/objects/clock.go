@@ -0,0 +1,75 @@
+package objects
+
+import "math"
+
+// Часы восхода и заката
+const (
+	SunriseHour = 6.0
+	SunsetHour  = 18.0
+)
+
+// SunSprite и MoonSprite - спрайты небесных светил
+var (
+	SunSprite = []string{
+		"\\|/",
+		"-O-",
+		"/|\\",
+	}
+	MoonSprite = []string{
+		" .-.",
+		"(  (",
+		" '-'",
+	}
+)
+
+// DayClock - игровые часы, задающие время суток
+type DayClock struct {
+	Hour  float64 // текущее время суток в часах [0, 24)
+	Speed float64 // игровых часов за секунду реального времени
+}
+
+// Clock - часы текущей миссии
+var Clock = DayClock{Hour: 12, Speed: 24.0 / 600}
+
+// NewDayClock создаёт часы, начинающиеся в startHour, у которых сутки длятся dayLength секунд
+func NewDayClock(startHour, dayLength float64) DayClock {
+	clock := DayClock{Hour: math.Mod(startHour, 24)}
+	if dayLength > 0 {
+		clock.Speed = 24 / dayLength
+	}
+	return clock
+}
+
+// Advance продвигает время суток на dt секунд реального времени
+func (c *DayClock) Advance(dt float64) {
+	c.Hour = math.Mod(c.Hour+c.Speed*dt, 24)
+}
+
+// SunElevation возвращает синус высоты солнца над горизонтом: 1 в полдень, -1 в полночь
+func (c *DayClock) SunElevation() float64 {
+	return math.Sin(math.Pi * (c.Hour - SunriseHour) / (SunsetHour - SunriseHour))
+}
+
+// Daylight возвращает освещённость от 0 (ночь) до 1 (день) с плавными сумерками
+func (c *DayClock) Daylight() float64 {
+	return math.Max(0, math.Min(1, (c.SunElevation()+0.1)/0.3))
+}
+
+// TwilightTint возвращает силу рассветного/закатного оттенка: максимум, когда солнце у горизонта
+func (c *DayClock) TwilightTint() float64 {
+	return math.Max(0, 1-math.Abs(c.SunElevation())/0.25)
+}
+
+// SunPosition возвращает долю пройденного солнцем пути по небу (0 - восход, 1 - закат)
+// и признак того, что солнце над горизонтом
+func (c *DayClock) SunPosition() (float64, bool) {
+	progress := (c.Hour - SunriseHour) / (SunsetHour - SunriseHour)
+	return progress, progress >= 0 && progress <= 1
+}
+
+// MoonPosition возвращает долю пройденного луной пути по небу; луна видна ночью
+func (c *DayClock) MoonPosition() (float64, bool) {
+	nightLength := 24 - (SunsetHour - SunriseHour)
+	progress := math.Mod(c.Hour-SunsetHour+24, 24) / nightLength
+	return progress, progress >= 0 && progress <= 1
+}
@@ -7,22 +7,27 @@ type Cloud struct {
 	Sprite []string
 }
 
+// Облачный слой: высоты над уровнем земли, в которых появляются облака
+const (
+	CloudBandBottom = 10
+	CloudBandTop    = 30
+)
+
 var CloudSprite = []string{
 	"  ~~  ",
 	"~~~~~~",
 	"  ~~  ",
 }
 
-var Clouds []Cloud
-
-// InitClouds генерирует n облаков в заданной зоне по оси Y (например, от 10 до 30)
-func InitClouds(n int) {
-	Clouds = make([]Cloud, n)
-	for i := 0; i < n; i++ {
-		Clouds[i] = Cloud{
-			X:      rand.Intn(WorldWidth),
-			Y:      10 + rand.Intn(20),
+// GenerateClouds создаёт n облаков в колонках [x0, x0+width) в облачном слое
+func GenerateClouds(rng *rand.Rand, x0, width, n int) []Cloud {
+	clouds := make([]Cloud, n)
+	for i := range clouds {
+		clouds[i] = Cloud{
+			X:      x0 + rng.Intn(width),
+			Y:      GroundLevel - CloudBandBottom - rng.Intn(CloudBandTop-CloudBandBottom),
 			Sprite: CloudSprite,
 		}
 	}
+	return clouds
 }
@@ -0,0 +1,83 @@
+package objects
+
+// CrashCause описывает причину крушения ракеты
+type CrashCause string
+
+const (
+	CrashHardLanding       CrashCause = "Hard landing"
+	CrashTippedOver        CrashCause = "Tipped over on slope"
+	CrashTerrain           CrashCause = "Flew into terrain"
+	CrashTree              CrashCause = "Hit a tree"
+	CrashAircraft          CrashCause = "Mid-air collision with aircraft"
+	CrashSatellite         CrashCause = "Collided with a satellite"
+	CrashDebris            CrashCause = "Struck by space debris"
+	CrashOverheat          CrashCause = "Burned up from overheating"
+	CrashStructuralFailure CrashCause = "Hull structural failure"
+)
+
+// CrashReport содержит сводку о крушении, показываемую игроку
+type CrashReport struct {
+	Cause           CrashCause // причина крушения
+	ImpactSpeed     float64    // скорость в момент удара
+	Altitude        float64    // высота в момент крушения
+	MaxAltitude     float64    // максимальная высота за полёт
+	FlightTime      float64    // длительность полёта в секундах
+	FuelLeft        float64    // остаток топлива
+	Stage           string     // активная ступень
+	AltitudeHistory []float64  // выборка высоты за полёт (от старых к новым)
+	Hull            float64    // целостность корпуса в момент крушения
+	EngineHealth    []float64  // состояние двигателей по ступеням
+	Temperature     float64    // температура в момент крушения
+}
+
+// AltitudeHistory хранит историю высоты с фиксированным шагом по времени.
+// Старые значения вытесняются, когда буфер заполнен.
+type AltitudeHistory struct {
+	Interval float64 // шаг записи в секундах
+	Capacity int     // максимальное число сохраняемых точек
+
+	samples []float64
+	timer   float64
+	max     float64
+}
+
+// NewAltitudeHistory создаёт историю с указанным шагом и ёмкостью
+func NewAltitudeHistory(interval float64, capacity int) *AltitudeHistory {
+	return &AltitudeHistory{Interval: interval, Capacity: capacity}
+}
+
+// Record учитывает текущую высоту; точка сохраняется раз в Interval секунд
+func (h *AltitudeHistory) Record(altitude, dt float64) {
+	if altitude > h.max {
+		h.max = altitude
+	}
+	h.timer -= dt
+	if h.timer > 0 && len(h.samples) > 0 {
+		return
+	}
+	h.timer = h.Interval
+	if len(h.samples) >= h.Capacity {
+		copy(h.samples, h.samples[1:])
+		h.samples = h.samples[:len(h.samples)-1]
+	}
+	h.samples = append(h.samples, altitude)
+}
+
+// Samples возвращает копию сохранённых точек
+func (h *AltitudeHistory) Samples() []float64 {
+	out := make([]float64, len(h.samples))
+	copy(out, h.samples)
+	return out
+}
+
+// Max возвращает максимальную высоту за время записи
+func (h *AltitudeHistory) Max() float64 {
+	return h.max
+}
+
+// Reset очищает историю перед новым полётом
+func (h *AltitudeHistory) Reset() {
+	h.samples = h.samples[:0]
+	h.timer = 0
+	h.max = 0
+}
@@ -0,0 +1,64 @@
+package objects
+
+// Параметры модели повреждений
+const (
+	MaxHull             = 100.0 // целостность корпуса неповреждённой ракеты
+	MaxEngineHealth     = 100.0 // состояние исправного двигателя
+	OverheatTemperature = 100.0 // температура, выше которой начинаются повреждения
+	MaxTemperature      = 200.0 // предельная температура для индикации
+)
+
+// ResetDamage восстанавливает корпус и двигатели всех ступеней и остужает ракету
+func (r *Rocket) ResetDamage() {
+	r.Hull = MaxHull
+	r.EngineHealth = make([]float64, len(RocketStages))
+	for i := range r.EngineHealth {
+		r.EngineHealth[i] = MaxEngineHealth
+	}
+	r.Temperature = 0
+}
+
+// ActiveEngineHealth возвращает состояние двигателя текущей ступени
+func (r *Rocket) ActiveEngineHealth() float64 {
+	if r.ActiveStage < 0 || r.ActiveStage >= len(r.EngineHealth) {
+		return MaxEngineHealth
+	}
+	return r.EngineHealth[r.ActiveStage]
+}
+
+// ThrustEfficiency возвращает долю тяги, которую выдаёт повреждённый двигатель текущей ступени.
+// Двигатель теряет мощность пропорционально износу, а полностью разрушенный не работает вовсе.
+func (r *Rocket) ThrustEfficiency() float64 {
+	return r.StageThrustEfficiency(r.ActiveStage)
+}
+
+// StageThrustEfficiency возвращает долю тяги двигателя ступени stage с учётом его износа
+func (r *Rocket) StageThrustEfficiency(stage int) float64 {
+	health := MaxEngineHealth
+	if stage >= 0 && stage < len(r.EngineHealth) {
+		health = r.EngineHealth[stage]
+	}
+	if health <= 0 {
+		return 0
+	}
+	return 0.4 + 0.6*health/MaxEngineHealth
+}
+
+// SteeringEfficiency возвращает долю мощности вспомогательных двигателей с учётом повреждений корпуса
+func (r *Rocket) SteeringEfficiency() float64 {
+	return 0.3 + 0.7*max(r.Hull, 0)/MaxHull
+}
+
+// DamageHull уменьшает целостность корпуса; возвращает true, если корпус разрушен
+func (r *Rocket) DamageHull(amount float64) bool {
+	r.Hull = max(r.Hull-amount, 0)
+	return r.Hull <= 0
+}
+
+// DamageEngine повреждает двигатель текущей ступени
+func (r *Rocket) DamageEngine(amount float64) {
+	if r.ActiveStage < 0 || r.ActiveStage >= len(r.EngineHealth) {
+		return
+	}
+	r.EngineHealth[r.ActiveStage] = max(r.EngineHealth[r.ActiveStage]-amount, 0)
+}
@@ -0,0 +1,232 @@
+package objects
+
+import "math"
+
+// Entity - идентификатор сущности. Сама сущность не хранит данных:
+// её состояние - это набор компонентов в хранилищах EntityWorld.
+type Entity uint32
+
+// Position - точная позиция левого верхнего угла спрайта сущности
+type Position struct {
+	X, Y float64
+}
+
+// Cell возвращает клетку, в которой рисуется сущность
+func (p Position) Cell() (int, int) {
+	return int(math.Floor(p.X)), int(math.Floor(p.Y))
+}
+
+// Velocity - скорость сущности
+type Velocity struct {
+	Vx, Vy float64
+}
+
+// Sprite - внешний вид сущности
+type Sprite struct {
+	Frames     [][]string // кадры анимации
+	LeftFrames [][]string // кадры при движении влево (если не заданы, используются Frames)
+	FrameRate  float64    // кадров в секунду (0 - без анимации)
+	Color      int32      // цвет в формате 0xRRGGBB
+}
+
+// Frame возвращает кадр спрайта для возраста age и горизонтальной скорости vx
+func (s *Sprite) Frame(age, vx float64) []string {
+	frames := s.Frames
+	if vx < 0 && len(s.LeftFrames) > 0 {
+		frames = s.LeftFrames
+	}
+	return frames[int(age*s.FrameRate)%len(frames)]
+}
+
+// Collider описывает, что происходит с ракетой при столкновении с сущностью.
+// Сущность при столкновении всегда разрушается.
+type Collider struct {
+	Cause      CrashCause // причина крушения, если удар смертелен или разрушил корпус
+	FatalSpeed float64    // относительная скорость, выше которой удар смертелен (0 - всегда, <0 - никогда)
+	Damage     float64    // повреждение корпуса при несмертельном ударе
+	Push       float64    // доля разницы скоростей, передаваемая ракете
+}
+
+// Fatal возвращает true, если удар на относительной скорости speed уничтожает ракету
+func (c *Collider) Fatal(speed float64) bool {
+	return c.FatalSpeed == 0 || (c.FatalSpeed > 0 && speed > c.FatalSpeed)
+}
+
+// Oscillation раскачивает сущность по вертикали вокруг BaseY
+type Oscillation struct {
+	BaseY     float64
+	Amplitude float64
+	Frequency float64 // радиан в секунду
+}
+
+// Wander иногда разворачивает сущность по горизонтали
+type Wander struct {
+	TurnRate float64 // средняя частота разворотов в секунду
+}
+
+// Lifetime - возраст сущности; по достижении MaxAge сущность удаляется
+type Lifetime struct {
+	Age    float64
+	MaxAge float64 // 0 - живёт, пока её не удалят
+}
+
+// Focus - ракета, вокруг которой спавнеры держат сущности: её колонка и высота
+type Focus struct {
+	X, Altitude int
+}
+
+// Spawner досоздаёт и удаляет сущности одного вида вокруг ракет
+type Spawner func(w *EntityWorld, focus []Focus)
+
+// EntityWorld хранит сущности и их компоненты. Системы (движение и столкновения в пакете physics,
+// отрисовка в пакете render) перебирают сущности по компонентам, поэтому новый вид объектов
+// описывается только набором компонентов и спавнером, зарегистрированным через physics.RegisterSpawner.
+type EntityWorld struct {
+	Positions    ComponentStore[Position]
+	Velocities   ComponentStore[Velocity]
+	Sprites      ComponentStore[Sprite]
+	Colliders    ComponentStore[Collider]
+	Oscillations ComponentStore[Oscillation]
+	Wanders      ComponentStore[Wander]
+	Lifetimes    ComponentStore[Lifetime]
+	Tags         ComponentStore[string] // вид сущности, по которому спавнеры считают свои объекты
+
+	Spawners []Spawner
+
+	next   Entity
+	doomed []Entity
+	stores []interface{ Remove(Entity) }
+}
+
+// Entities - сущности текущего мира
+var Entities = NewEntityWorld()
+
+// NewEntityWorld создаёт пустой мир сущностей
+func NewEntityWorld() *EntityWorld {
+	w := &EntityWorld{}
+	w.stores = []interface{ Remove(Entity) }{
+		&w.Positions, &w.Velocities, &w.Sprites, &w.Colliders,
+		&w.Oscillations, &w.Wanders, &w.Lifetimes, &w.Tags,
+	}
+	return w
+}
+
+// Spawn создаёт новую сущность без компонентов
+func (w *EntityWorld) Spawn() Entity {
+	w.next++
+	return w.next
+}
+
+// Destroy помечает сущность на удаление; она исчезнет при следующем Flush.
+// Удаление отложено, чтобы системы могли уничтожать сущности во время перебора.
+func (w *EntityWorld) Destroy(e Entity) {
+	w.doomed = append(w.doomed, e)
+}
+
+// Flush удаляет помеченные сущности из всех хранилищ
+func (w *EntityWorld) Flush() {
+	for _, e := range w.doomed {
+		for _, store := range w.stores {
+			store.Remove(e)
+		}
+	}
+	w.doomed = w.doomed[:0]
+}
+
+// Populate вызывает все спавнеры для ракет focus
+func (w *EntityWorld) Populate(focus ...Focus) {
+	for _, spawn := range w.Spawners {
+		spawn(w, focus)
+	}
+	w.Flush()
+}
+
+// SpriteOf возвращает текущий кадр спрайта сущности
+func (w *EntityWorld) SpriteOf(e Entity) []string {
+	sprite := w.Sprites.Get(e)
+	if sprite == nil {
+		return nil
+	}
+	age, vx := 0.0, 0.0
+	if life := w.Lifetimes.Get(e); life != nil {
+		age = life.Age
+	}
+	if vel := w.Velocities.Get(e); vel != nil {
+		vx = vel.Vx
+	}
+	return sprite.Frame(age, vx)
+}
+
+// Colliding возвращает сущности с коллайдером, спрайт которых пересекается со спрайтом в (x, y)
+func (w *EntityWorld) Colliding(x, y int, sprite []string) []Entity {
+	var hits []Entity
+	w.Colliders.Each(func(e Entity, _ *Collider) {
+		pos := w.Positions.Get(e)
+		if pos == nil {
+			return
+		}
+		ex, ey := pos.Cell()
+		if other := w.SpriteOf(e); other != nil && SpritesOverlap(x, y, sprite, ex, ey, other) {
+			hits = append(hits, e)
+		}
+	})
+	return hits
+}
+
+// ComponentStore хранит компоненты одного типа плотным массивом для быстрого перебора
+type ComponentStore[T any] struct {
+	items  []T
+	owners []Entity
+	index  map[Entity]int
+}
+
+// Set добавляет или заменяет компонент сущности
+func (s *ComponentStore[T]) Set(e Entity, c T) {
+	if i, ok := s.index[e]; ok {
+		s.items[i] = c
+		return
+	}
+	if s.index == nil {
+		s.index = make(map[Entity]int)
+	}
+	s.index[e] = len(s.items)
+	s.items = append(s.items, c)
+	s.owners = append(s.owners, e)
+}
+
+// Get возвращает компонент сущности или nil, если его нет
+func (s *ComponentStore[T]) Get(e Entity) *T {
+	i, ok := s.index[e]
+	if !ok {
+		return nil
+	}
+	return &s.items[i]
+}
+
+// Remove удаляет компонент сущности, переставляя на его место последний
+func (s *ComponentStore[T]) Remove(e Entity) {
+	i, ok := s.index[e]
+	if !ok {
+		return
+	}
+	last := len(s.items) - 1
+	s.items[i] = s.items[last]
+	s.owners[i] = s.owners[last]
+	s.index[s.owners[i]] = i
+	s.items = s.items[:last]
+	s.owners = s.owners[:last]
+	delete(s.index, e)
+}
+
+// Each вызывает visit для каждого компонента. Добавлять и удалять компоненты
+// этого типа внутри visit нельзя - для удаления сущностей есть EntityWorld.Destroy.
+func (s *ComponentStore[T]) Each(visit func(e Entity, c *T)) {
+	for i := range s.items {
+		visit(s.owners[i], &s.items[i])
+	}
+}
+
+// Len возвращает число компонентов
+func (s *ComponentStore[T]) Len() int {
+	return len(s.items)
+}
@@ -0,0 +1,25 @@
+package objects
+
+import "math"
+
+// valueNoise - одномерный value noise со сглаженной интерполяцией, значения в [0, 1).
+// Результат полностью определяется зерном, координатой и номером октавы.
+func valueNoise(seed int64, x float64, octave uint64) float64 {
+	i := math.Floor(x)
+	f := x - i
+	f = f * f * (3 - 2*f)
+	a := lattice(seed, int64(i), octave)
+	b := lattice(seed, int64(i)+1, octave)
+	return a + (b-a)*f
+}
+
+// lattice возвращает псевдослучайное значение в узле решётки (хеш splitmix64)
+func lattice(seed int64, i int64, octave uint64) float64 {
+	h := uint64(i)*0x9E3779B97F4A7C15 ^ uint64(seed) ^ octave*0xBF58476D1CE4E5B9
+	h ^= h >> 30
+	h *= 0xBF58476D1CE4E5B9
+	h ^= h >> 27
+	h *= 0x94D049BB133111EB
+	h ^= h >> 31
+	return float64(h>>11) / (1 << 53)
+}
@@ -0,0 +1,185 @@
+package objects
+
+import (
+	"math"
+	"math/rand"
+)
+
+// ObstacleKind определяет тип препятствия
+type ObstacleKind int
+
+const (
+	ObstacleBird      ObstacleKind = iota // птицы у земли
+	ObstacleAircraft                      // самолёты в тропосфере
+	ObstacleSatellite                     // спутники выше линии Кармана
+	ObstacleDebris                        // космический мусор выше линии Кармана
+)
+
+// String возвращает тег сущностей препятствия
+func (k ObstacleKind) String() string {
+	switch k {
+	case ObstacleBird:
+		return "bird"
+	case ObstacleAircraft:
+		return "aircraft"
+	case ObstacleSatellite:
+		return "satellite"
+	default:
+		return "debris"
+	}
+}
+
+// ObstacleBand описывает, где и в каком количестве встречаются препятствия одного типа
+type ObstacleBand struct {
+	Kind        ObstacleKind
+	MinAltitude int     // нижняя граница слоя (высота над GroundLevel)
+	MaxAltitude int     // верхняя граница слоя
+	Count       int     // сколько препятствий держать вокруг ракеты
+	MinSpeed    float64 // минимальная горизонтальная скорость
+	MaxSpeed    float64 // максимальная горизонтальная скорость
+}
+
+// ObstacleBands - слои препятствий. 1000 единиц высоты соответствуют 100 км (линия Кармана).
+var ObstacleBands = []ObstacleBand{
+	{Kind: ObstacleBird, MinAltitude: 8, MaxAltitude: 40, Count: 12, MinSpeed: 4, MaxSpeed: 8},
+	{Kind: ObstacleAircraft, MinAltitude: 60, MaxAltitude: 120, Count: 3, MinSpeed: 20, MaxSpeed: 35},
+	{Kind: ObstacleSatellite, MinAltitude: 1000, MaxAltitude: 1600, Count: 4, MinSpeed: 30, MaxSpeed: 60},
+	{Kind: ObstacleDebris, MinAltitude: 1000, MaxAltitude: 1600, Count: 15, MinSpeed: 1, MaxSpeed: 6},
+}
+
+// Радиусы, в которых препятствия существуют вокруг ракеты
+const (
+	obstacleRadius      = 400 // по горизонтали
+	obstacleBandMargin  = 200 // по вертикали от границ слоя
+	obstacleMinDistance = 80  // новые препятствия не появляются ближе этого, чтобы не возникать на экране
+	birdFlockSize       = 4
+)
+
+// Спрайты препятствий
+var (
+	BirdSprites           = [][]string{{"\\v/"}, {"-v-"}}
+	AircraftSpriteRight   = []string{"  |\\   ", "=[___o>"}
+	AircraftSpriteLeft    = []string{"   /|  ", "<o___]="}
+	SatelliteSprite       = []string{"[#]-o-[#]"}
+	DebrisSprites         = [][]string{{"%"}, {"#"}, {"*"}}
+	obstacleAnimationRate = 6.0 // кадров анимации в секунду
+)
+
+// Последствия столкновения ракеты с препятствиями. Птицы и медленный мусор разрушаются,
+// повреждая корпус и сбивая ракету с курса; самолёты, спутники и быстрый мусор уничтожают ракету.
+var (
+	birdCollider      = Collider{Cause: CrashStructuralFailure, FatalSpeed: -1, Damage: 10, Push: 0.2}
+	aircraftCollider  = Collider{Cause: CrashAircraft}
+	satelliteCollider = Collider{Cause: CrashSatellite}
+	debrisCollider    = Collider{Cause: CrashDebris, FatalSpeed: 10, Damage: 20, Push: 0.2}
+)
+
+// ObstacleSpawner возвращает спавнер препятствий из слоёв bands
+func ObstacleSpawner(bands []ObstacleBand) Spawner {
+	return func(w *EntityWorld, focus []Focus) {
+		PopulateObstacles(w, focus, bands)
+	}
+}
+
+// PopulateObstacles удаляет препятствия, далёкие от всех ракет, и досоздаёт новые
+// в слоях из bands, находящихся рядом с каждой ракетой.
+func PopulateObstacles(w *EntityWorld, focus []Focus, bands []ObstacleBand) {
+	// counts[i] - число препятствий каждого вида рядом с ракетой focus[i]
+	counts := make([]map[string]int, len(focus))
+	for i := range counts {
+		counts[i] = make(map[string]int)
+	}
+	w.Tags.Each(func(e Entity, tag *string) {
+		band, ok := bandOf(*tag, bands)
+		if !ok {
+			return
+		}
+		pos := w.Positions.Get(e)
+		near := false
+		for i, f := range focus {
+			if math.Abs(pos.X-float64(f.X)) <= 2*obstacleRadius && band.near(f.Altitude) {
+				counts[i][*tag]++
+				near = true
+			}
+		}
+		if !near {
+			w.Destroy(e)
+		}
+	})
+
+	for i, f := range focus {
+		for _, band := range bands {
+			if !band.near(f.Altitude) {
+				continue
+			}
+			for count := counts[i][band.Kind.String()]; count < band.Count; {
+				count += spawnObstacle(w, band, f.X)
+			}
+		}
+	}
+}
+
+// spawnObstacle создаёт препятствие (или стаю птиц) и возвращает число созданных объектов
+func spawnObstacle(w *EntityWorld, band ObstacleBand, centerX int) int {
+	side := 1.0
+	if rand.Intn(2) == 0 {
+		side = -1
+	}
+	x := float64(centerX) + side*float64(obstacleMinDistance+rand.Intn(obstacleRadius-obstacleMinDistance))
+	y := float64(GroundLevel - band.MinAltitude - rand.Intn(band.MaxAltitude-band.MinAltitude+1))
+	speed := band.MinSpeed + rand.Float64()*(band.MaxSpeed-band.MinSpeed)
+	// Большинство препятствий летят навстречу ракете, чтобы их было видно
+	vx := -side * speed
+	if rand.Intn(3) == 0 {
+		vx = -vx
+	}
+
+	n := 1
+	if band.Kind == ObstacleBird {
+		n = 1 + rand.Intn(birdFlockSize)
+	}
+	for i := 0; i < n; i++ {
+		e := w.Spawn()
+		oy := y + float64(i%2)
+		w.Tags.Set(e, band.Kind.String())
+		w.Positions.Set(e, Position{X: x + float64(i*4), Y: oy})
+		w.Velocities.Set(e, Velocity{Vx: vx})
+		w.Lifetimes.Set(e, Lifetime{Age: rand.Float64()})
+
+		switch band.Kind {
+		case ObstacleBird:
+			// Птицы летят волной и иногда разворачиваются
+			w.Sprites.Set(e, Sprite{Frames: BirdSprites, FrameRate: obstacleAnimationRate, Color: 0x800000})
+			w.Colliders.Set(e, birdCollider)
+			w.Oscillations.Set(e, Oscillation{BaseY: oy, Amplitude: 1.5, Frequency: 3})
+			w.Wanders.Set(e, Wander{TurnRate: 0.05})
+		case ObstacleAircraft:
+			w.Sprites.Set(e, Sprite{Frames: [][]string{AircraftSpriteRight}, LeftFrames: [][]string{AircraftSpriteLeft}, Color: 0xC0C0C0})
+			w.Colliders.Set(e, aircraftCollider)
+		case ObstacleSatellite:
+			w.Sprites.Set(e, Sprite{Frames: [][]string{SatelliteSprite}, Color: 0x00FFFF})
+			w.Colliders.Set(e, satelliteCollider)
+		case ObstacleDebris:
+			// Мусор летит по прямой под небольшим углом к горизонту и направления не меняет
+			w.Velocities.Set(e, Velocity{Vx: vx, Vy: (rand.Float64()*2 - 1) * band.MaxSpeed / 3})
+			w.Sprites.Set(e, Sprite{Frames: DebrisSprites, FrameRate: obstacleAnimationRate, Color: 0x808080})
+			w.Colliders.Set(e, debrisCollider)
+		}
+	}
+	return n
+}
+
+// near возвращает true, если высота находится в слое или рядом с ним
+func (b ObstacleBand) near(altitude int) bool {
+	return altitude >= b.MinAltitude-obstacleBandMargin && altitude <= b.MaxAltitude+obstacleBandMargin
+}
+
+// bandOf возвращает слой для препятствия с тегом tag среди bands
+func bandOf(tag string, bands []ObstacleBand) (ObstacleBand, bool) {
+	for _, band := range bands {
+		if band.Kind.String() == tag {
+			return band, true
+		}
+	}
+	return ObstacleBand{}, false
+}
@@ -0,0 +1,104 @@
+package objects
+
+import (
+	"math"
+	"math/rand"
+)
+
+// ParticleKind определяет тип частицы (от него зависят физика и внешний вид)
+type ParticleKind int
+
+const (
+	ParticleFlame    ParticleKind = iota // пламя главного двигателя
+	ParticleThruster                     // струя вспомогательных двигателей
+	ParticleSmoke                        // дымовой след, долго висящий в мире
+	ParticleDebris                       // обломки при взрыве
+)
+
+// Particle - одна частица в мировых координатах
+type Particle struct {
+	X, Y    float64      // позиция в мировых координатах
+	Vx, Vy  float64      // скорость (положительная Vy - вниз)
+	Life    float64      // оставшееся время жизни в секундах
+	MaxLife float64      // начальное время жизни
+	Kind    ParticleKind // тип частицы
+}
+
+// Age возвращает долю прожитой жизни частицы от 0 (новая) до 1 (умирает)
+func (p *Particle) Age() float64 {
+	if p.MaxLife <= 0 {
+		return 1
+	}
+	return 1 - p.Life/p.MaxLife
+}
+
+// MaxParticles ограничивает общее число живых частиц
+const MaxParticles = 3000
+
+var Particles []Particle
+
+// SpawnParticle добавляет частицу в мир, если не превышен лимит
+func SpawnParticle(p Particle) {
+	if len(Particles) >= MaxParticles {
+		return
+	}
+	Particles = append(Particles, p)
+}
+
+// Emitter описывает источник частиц одного типа
+type Emitter struct {
+	Kind       ParticleKind
+	Rate       float64 // частиц в секунду при интенсивности 1.0
+	Speed      float64 // базовая скорость вылета
+	Spread     float64 // разброс направления в радианах
+	Life       float64 // базовое время жизни в секундах
+	LifeJitter float64 // случайная добавка ко времени жизни
+}
+
+// Предустановленные излучатели
+var (
+	EngineEmitter   = Emitter{Kind: ParticleFlame, Rate: 120, Speed: 18, Spread: 0.35, Life: 0.25, LifeJitter: 0.3}
+	ThrusterEmitter = Emitter{Kind: ParticleThruster, Rate: 60, Speed: 14, Spread: 0.2, Life: 0.15, LifeJitter: 0.15}
+	SmokeEmitter    = Emitter{Kind: ParticleSmoke, Rate: 25, Speed: 2, Spread: 1.2, Life: 4.0, LifeJitter: 3.0}
+	DebrisEmitter   = Emitter{Kind: ParticleDebris, Speed: 25, Spread: math.Pi, Life: 1.5, LifeJitter: 2.0}
+)
+
+// Emit выпускает частицы из точки (x, y) в направлении (dirX, dirY).
+// Количество частиц пропорционально intensity и dt; дробная часть
+// разыгрывается случайно, поэтому излучателю не нужно хранить состояние.
+func (e *Emitter) Emit(x, y, dirX, dirY, baseVx, baseVy, intensity, dt float64) {
+	if intensity <= 0 {
+		return
+	}
+	count := e.Rate * intensity * dt
+	n := int(count)
+	if rand.Float64() < count-float64(n) {
+		n++
+	}
+	e.Burst(x, y, dirX, dirY, baseVx, baseVy, n)
+}
+
+// Burst выпускает сразу n частиц (например, обломки при взрыве)
+func (e *Emitter) Burst(x, y, dirX, dirY, baseVx, baseVy float64, n int) {
+	baseAngle := math.Atan2(dirY, dirX)
+	for i := 0; i < n; i++ {
+		angle := baseAngle + (rand.Float64()*2-1)*e.Spread
+		speed := e.Speed * (0.5 + rand.Float64())
+		life := e.Life + rand.Float64()*e.LifeJitter
+		SpawnParticle(Particle{
+			X:       x,
+			Y:       y,
+			Vx:      baseVx + math.Cos(angle)*speed,
+			Vy:      baseVy + math.Sin(angle)*speed,
+			Life:    life,
+			MaxLife: life,
+			Kind:    e.Kind,
+		})
+	}
+}
+
+// EmitExplosion разбрасывает обломки и дым вокруг точки взрыва
+func EmitExplosion(x, y float64) {
+	DebrisEmitter.Burst(x, y, 0, -1, 0, 0, 80)
+	SmokeEmitter.Burst(x, y, 0, -1, 0, 0, 40)
+}
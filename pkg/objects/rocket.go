@@ -1,15 +1,33 @@
 package objects
 
+import "math"
+
 // Rocket описывает состояние ракеты
 type Rocket struct {
-	X, Y         int     // позиция (левый верхний угол спрайта)
-	Vx, Vy       float64 // скорости по осям X и Y
-	ThrustX      float64 // тяга по горизонтали (положительное значение – вправо)
-	ThrustY      float64 // тяга по вертикали (для подъёма; базовая равна HoverThrust)
-	Fuel         float64 // оставшееся топливо
-	AccumulatedX float64 // аккумулятор дробных перемещений по X
-	AccumulatedY float64 // аккумулятор дробных перемещений по Y
-	ActiveStage  int     // индекс текущей активной ступени
+	X, Y             int       // клетка левого верхнего угла спрайта; меняется только через SetPosition
+	PosX, PosY       float64   // точная позиция левого верхнего угла спрайта в мире; меняется только через SetPosition
+	Vx, Vy           float64   // скорости по осям X и Y
+	ThrustX          float64   // тяга по горизонтали (положительное значение – вправо)
+	ThrustY          float64   // тяга по вертикали (для подъёма; базовая равна HoverThrust)
+	Fuel             float64   // оставшееся топливо
+	ActiveStage      int       // индекс текущей активной ступени
+	ImpactSpeed      float64   // скорость касания земли на последнем шаге (0 - касания не было)
+	GroundSlope      int       // перепад высот рельефа под опорой при касании
+	TerrainCollision bool      // ракета врезалась в склон сбоку на последнем шаге
+	Hull             float64   // целостность корпуса (0..MaxHull)
+	EngineHealth     []float64 // состояние двигателя каждой ступени (0..MaxEngineHealth)
+	Temperature      float64   // температура корпуса и двигателя
+}
+
+// Position возвращает точную позицию ракеты
+func (r *Rocket) Position() (float64, float64) {
+	return r.PosX, r.PosY
+}
+
+// SetPosition задаёт точную позицию ракеты и клетку, в которой она рисуется
+func (r *Rocket) SetPosition(x, y float64) {
+	r.PosX, r.PosY = x, y
+	r.X, r.Y = int(math.Floor(x)), int(math.Floor(y))
 }
 
 // RocketBody - основная часть спрайта ракеты (без нижней части)
@@ -25,16 +43,16 @@ func (r *Rocket) GetRocketSprite() []string {
 	if r.ActiveStage < 0 || r.ActiveStage >= len(RocketStages) {
 		r.ActiveStage = 0
 	}
-	
+
 	// Создаём полный спрайт, объединяя корпус и нижнюю часть текущей ступени
 	fullSprite := make([]string, len(RocketBody)+len(RocketStages[r.ActiveStage].BottomSprite))
-	
+
 	// Копируем верхнюю часть (корпус)
 	copy(fullSprite, RocketBody)
-	
+
 	// Копируем нижнюю часть (текущей ступени)
 	copy(fullSprite[len(RocketBody):], RocketStages[r.ActiveStage].BottomSprite)
-	
+
 	return fullSprite
 }
 
@@ -0,0 +1,157 @@
+package objects
+
+// Rect - прямоугольник, выровненный по осям (AABB), в мировых координатах
+type Rect struct {
+	X, Y int // левый верхний угол
+	W, H int // ширина и высота
+}
+
+// SpriteRect возвращает прямоугольник, занимаемый спрайтом в позиции (x, y)
+func SpriteRect(x, y int, sprite []string) Rect {
+	width := 0
+	for _, line := range sprite {
+		width = max(width, len([]rune(line)))
+	}
+	return Rect{X: x, Y: y, W: width, H: len(sprite)}
+}
+
+// Intersects возвращает true, если прямоугольники пересекаются
+func (r Rect) Intersects(o Rect) bool {
+	return r.X < o.X+o.W && o.X < r.X+r.W &&
+		r.Y < o.Y+o.H && o.Y < r.Y+r.H
+}
+
+// SpritesOverlap проверяет клетка за клеткой, перекрываются ли непустые символы двух спрайтов
+func SpritesOverlap(ax, ay int, a []string, bx, by int, b []string) bool {
+	if !SpriteRect(ax, ay, a).Intersects(SpriteRect(bx, by, b)) {
+		return false
+	}
+	for dy, line := range a {
+		row := ay + dy - by
+		if row < 0 || row >= len(b) {
+			continue
+		}
+		other := []rune(b[row])
+		for dx, ch := range []rune(line) {
+			col := ax + dx - bx
+			if ch != ' ' && col >= 0 && col < len(other) && other[col] != ' ' {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// cellKey - координаты ячейки сетки
+type cellKey struct {
+	X, Y int
+}
+
+// gridItem - объект в сетке вместе с его границами
+type gridItem[T any] struct {
+	bounds Rect
+	value  T
+	stamp  int // номер последнего запроса, в котором объект уже был выдан
+}
+
+// SpatialGrid - равномерная сетка для быстрых запросов объектов по области.
+// Объект заносится во все ячейки, которые пересекает, поэтому стоимость
+// запроса зависит от размера области, а не от общего числа объектов.
+type SpatialGrid[T any] struct {
+	CellSize int
+
+	cells  map[cellKey][]int
+	items  map[int]*gridItem[T]
+	nextID int
+	stamp  int
+}
+
+// NewSpatialGrid создаёт пустую сетку с ячейками cellSize x cellSize
+func NewSpatialGrid[T any](cellSize int) *SpatialGrid[T] {
+	return &SpatialGrid[T]{
+		CellSize: cellSize,
+		cells:    make(map[cellKey][]int),
+		items:    make(map[int]*gridItem[T]),
+	}
+}
+
+// Len возвращает число объектов в сетке
+func (g *SpatialGrid[T]) Len() int {
+	return len(g.items)
+}
+
+// Insert добавляет объект с границами bounds и возвращает его идентификатор для Remove
+func (g *SpatialGrid[T]) Insert(bounds Rect, value T) int {
+	id := g.nextID
+	g.nextID++
+	g.items[id] = &gridItem[T]{bounds: bounds, value: value}
+	g.forEachCell(bounds, func(key cellKey) {
+		g.cells[key] = append(g.cells[key], id)
+	})
+	return id
+}
+
+// Remove удаляет объект по идентификатору, полученному от Insert
+func (g *SpatialGrid[T]) Remove(id int) {
+	item, ok := g.items[id]
+	if !ok {
+		return
+	}
+	delete(g.items, id)
+	g.forEachCell(item.bounds, func(key cellKey) {
+		ids := g.cells[key]
+		for i, other := range ids {
+			if other == id {
+				ids[i] = ids[len(ids)-1]
+				ids = ids[:len(ids)-1]
+				break
+			}
+		}
+		if len(ids) == 0 {
+			delete(g.cells, key)
+		} else {
+			g.cells[key] = ids
+		}
+	})
+}
+
+// Query вызывает visit для каждого объекта, чьи границы пересекают area.
+// Каждый объект выдаётся не более одного раза.
+func (g *SpatialGrid[T]) Query(area Rect, visit func(value T, bounds Rect)) {
+	g.stamp++
+	g.forEachCell(area, func(key cellKey) {
+		for _, id := range g.cells[key] {
+			item := g.items[id]
+			if item.stamp == g.stamp {
+				continue
+			}
+			item.stamp = g.stamp
+			if item.bounds.Intersects(area) {
+				visit(item.value, item.bounds)
+			}
+		}
+	})
+}
+
+// forEachCell перебирает ячейки сетки, которые пересекает прямоугольник
+func (g *SpatialGrid[T]) forEachCell(r Rect, fn func(key cellKey)) {
+	if r.W <= 0 || r.H <= 0 {
+		return
+	}
+	x0, y0 := floorDiv(r.X, g.CellSize), floorDiv(r.Y, g.CellSize)
+	x1, y1 := floorDiv(r.X+r.W-1, g.CellSize), floorDiv(r.Y+r.H-1, g.CellSize)
+	for cy := y0; cy <= y1; cy++ {
+		for cx := x0; cx <= x1; cx++ {
+			fn(cellKey{cx, cy})
+		}
+	}
+}
+
+// floorDiv - целочисленное деление с округлением вниз (для отрицательных координат)
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
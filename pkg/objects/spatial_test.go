@@ -0,0 +1,114 @@
+package objects
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// benchSizes - число объектов в сетке для бенчмарков
+var benchSizes = []int{1_000, 10_000, 100_000}
+
+// benchDensity - сколько клеток мира приходится на один объект; мир растёт вместе с числом объектов
+const benchDensity = 400
+
+// randomRects возвращает n прямоугольников размером со спрайт в квадрате со стороной side
+func randomRects(rng *rand.Rand, n, side int) []Rect {
+	rects := make([]Rect, n)
+	for i := range rects {
+		rects[i] = Rect{X: rng.Intn(side) - side/2, Y: rng.Intn(side) - side/2, W: 1 + rng.Intn(8), H: 1 + rng.Intn(4)}
+	}
+	return rects
+}
+
+// worldSide возвращает сторону мира, в котором n объектов лежат с плотностью benchDensity
+func worldSide(n int) int {
+	side := 1
+	for side*side < n*benchDensity {
+		side++
+	}
+	return side
+}
+
+func TestSpatialGridQuery(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	const side = 400
+	rects := randomRects(rng, 2000, side)
+	grid := NewSpatialGrid[int](sceneryCellSize)
+	ids := make([]int, len(rects))
+	for i, r := range rects {
+		ids[i] = grid.Insert(r, i)
+	}
+	// Удаляем каждый третий объект, чтобы проверить и Remove
+	for i := 0; i < len(rects); i += 3 {
+		grid.Remove(ids[i])
+	}
+	if want := len(rects) - (len(rects)+2)/3; grid.Len() != want {
+		t.Fatalf("Len() = %d, want %d", grid.Len(), want)
+	}
+
+	for q := 0; q < 200; q++ {
+		area := Rect{X: rng.Intn(side) - side/2, Y: rng.Intn(side) - side/2, W: 1 + rng.Intn(120), H: 1 + rng.Intn(40)}
+		var got, want []int
+		grid.Query(area, func(i int, _ Rect) { got = append(got, i) })
+		for i, r := range rects {
+			if i%3 != 0 && r.Intersects(area) {
+				want = append(want, i)
+			}
+		}
+		sort.Ints(got)
+		if fmt.Sprint(got) != fmt.Sprint(want) {
+			t.Fatalf("Query(%+v) = %v, want %v", area, got, want)
+		}
+	}
+}
+
+func BenchmarkSpatialGridQuery(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("%dk", n/1000), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			side := worldSide(n)
+			grid := NewSpatialGrid[int](sceneryCellSize)
+			for i, r := range randomRects(rng, n, side) {
+				grid.Insert(r, i)
+			}
+			// Запрос размером с экран терминала
+			areas := randomRects(rng, 1024, side)
+			for i := range areas {
+				areas[i].W, areas[i].H = 120, 40
+			}
+			found := 0
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				grid.Query(areas[i%len(areas)], func(int, Rect) { found++ })
+			}
+			b.ReportMetric(float64(found)/float64(b.N), "objects/op")
+		})
+	}
+}
+
+func BenchmarkSpatialGridUpdate(b *testing.B) {
+	for _, n := range benchSizes {
+		b.Run(fmt.Sprintf("%dk", n/1000), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1))
+			side := worldSide(n)
+			grid := NewSpatialGrid[int](sceneryCellSize)
+			rects := randomRects(rng, n, side)
+			ids := make([]int, n)
+			for i, r := range rects {
+				ids[i] = grid.Insert(r, i)
+			}
+			// Обновление - перенос объекта на несколько клеток: удаление и вставка с новыми границами
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				k := i % n
+				r := &rects[k]
+				r.X += rng.Intn(7) - 3
+				r.Y += rng.Intn(5) - 2
+				grid.Remove(ids[k])
+				ids[k] = grid.Insert(*r, k)
+			}
+		})
+	}
+}
@@ -1,33 +1,25 @@
 package objects
 
-import "math/rand"
-
-type Star struct {
-	X, Y int
-}
-
-var Stars []Star
-
-// Параметры мира (при необходимости можно вынести в отдельный конфиг)
+// Параметры мира (при необходимости можно вынести в отдельный конфиг).
+// Мир бесконечен по горизонтали, WorldWidth лишь задаёт положение стартовой площадки в его "центре".
 var WorldWidth, WorldHeight int = 10000, 20000
 var GroundLevel int = 1
 
-// InitStars генерирует массив звёзд
-func InitStars(n int) {
-	Stars = make([]Star, n)
-	for i := 0; i < n; i++ {
-		Stars[i] = Star{
-			X: rand.Intn(WorldWidth),
-			Y: rand.Intn(GroundLevel),
-		}
-	}
-}
-
 // IsStarAt возвращает true, если в мировых координатах (x, y) должна быть звезда.
 func IsStarAt(x, y int) bool {
+	return starHash(x, y)%100 < 3
+}
+
+// StarBrightness возвращает яркость звезды в (x, y) от 0 (самая яркая) до 1 (самая тусклая).
+// При плохой видимости показываются только самые яркие звёзды.
+func StarBrightness(x, y int) float64 {
+	return float64(starHash(x, y)/100%1000) / 1000
+}
+
+func starHash(x, y int) int64 {
 	h := int64(x)*73856093 ^ int64(y)*19349663
 	if h < 0 {
 		h = -h
 	}
-	return h%100 < 3
+	return h
 }
@@ -0,0 +1,90 @@
+package objects
+
+import "math"
+
+// Terrain описывает рельеф поверхности, заданный картой высот.
+// Высота вычисляется детерминированно из Seed, поэтому рельеф не нужно хранить в памяти.
+type Terrain struct {
+	Seed      int64
+	BaseLevel int // уровень "моря" - от него отсчитывается высота рельефа (Y растёт вниз)
+
+	LaunchX    int // центр стартовой площадки
+	PadSpacing int // расстояние между посадочными площадками
+	PadWidth   int // ширина посадочной площадки
+
+	HillHeight     float64 // амплитуда холмов
+	MountainHeight float64 // амплитуда гор
+}
+
+// Ground - рельеф текущего мира
+var Ground Terrain
+
+// InitTerrain создаёт рельеф по зерну с площадкой для старта в центре мира
+func InitTerrain(seed int64) {
+	Ground = Terrain{
+		Seed:           seed,
+		BaseLevel:      GroundLevel,
+		LaunchX:        WorldWidth / 2,
+		PadSpacing:     300,
+		PadWidth:       16,
+		HillHeight:     6,
+		MountainHeight: 45,
+	}
+}
+
+// SurfaceAt возвращает Y верхней твёрдой клетки рельефа в колонке x
+func (t *Terrain) SurfaceAt(x int) int {
+	if t.PadSpacing > 0 {
+		if center, ok := t.padCenter(x); ok {
+			return t.BaseLevel - t.elevation(center)
+		}
+	}
+	return t.BaseLevel - t.elevation(x)
+}
+
+// IsPad возвращает true, если колонка x принадлежит ровной посадочной площадке
+func (t *Terrain) IsPad(x int) bool {
+	_, ok := t.padCenter(x)
+	return ok
+}
+
+// padCenter возвращает центр площадки, которой принадлежит колонка x
+func (t *Terrain) padCenter(x int) (int, bool) {
+	if t.PadSpacing <= 0 {
+		return 0, false
+	}
+	offset := x - t.LaunchX
+	// Ближайший центр площадки с учётом отрицательных смещений
+	k := int(math.Floor(float64(offset)/float64(t.PadSpacing) + 0.5))
+	center := t.LaunchX + k*t.PadSpacing
+	if abs(x-center) <= t.PadWidth/2 {
+		return center, true
+	}
+	return 0, false
+}
+
+// elevation возвращает высоту рельефа над BaseLevel в колонке x (без учёта площадок)
+func (t *Terrain) elevation(x int) int {
+	fx := float64(x)
+	hills := t.HillHeight * (t.noise(fx/40, 0)*0.7 + t.noise(fx/11, 1)*0.3)
+
+	// Горы появляются только там, где низкочастотный шум превышает порог
+	ridge := (t.noise(fx/250, 2) - 0.55) / 0.45
+	mountains := 0.0
+	if ridge > 0 {
+		mountains = t.MountainHeight * ridge * ridge
+	}
+	return int(math.Round(hills + mountains))
+}
+
+// noise возвращает значение шума рельефа для октавы octave
+func (t *Terrain) noise(x float64, octave uint64) float64 {
+	return valueNoise(t.Seed, x, octave)
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -13,16 +13,31 @@ var TreeSprite = []string{
 	"  |  ",
 }
 
-var Trees []Tree
-
-// InitTrees генерирует n деревьев в зоне около земли.
-func InitTrees(n int) {
-	Trees = make([]Tree, n)
+// GenerateTrees размещает до n деревьев на рельефе в колонках [x0, x0+width).
+// Используется генератором чанков, поэтому всю случайность берёт из rng.
+func GenerateTrees(rng *rand.Rand, terrain *Terrain, x0, width, n int) []Tree {
+	trees := make([]Tree, 0, n)
 	for i := 0; i < n; i++ {
-		Trees[i] = Tree{
-			X:      rand.Intn(WorldWidth),
-			Y:      GroundLevel - len(TreeSprite), // чтобы дерево "стоило" на земле
-			Sprite: TreeSprite,
+		x := x0 + rng.Intn(width)
+		// Деревья не растут на посадочных площадках
+		if terrain.IsPad(x) || terrain.IsPad(x+len(TreeSprite[0])-1) {
+			continue
 		}
+		trees = append(trees, Tree{
+			X:      x,
+			Y:      treeBaseAt(terrain, x) - len(TreeSprite), // чтобы дерево "стояло" на рельефе
+			Sprite: TreeSprite,
+		})
+	}
+	return trees
+}
+
+// treeBaseAt возвращает самую высокую поверхность под стволом и кроной,
+// чтобы дерево не "висело" над склоном и не уходило в него
+func treeBaseAt(terrain *Terrain, x int) int {
+	base := terrain.SurfaceAt(x)
+	for dx := 1; dx < len(TreeSprite[0]); dx++ {
+		base = min(base, terrain.SurfaceAt(x+dx))
 	}
+	return base
 }
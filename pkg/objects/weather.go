@@ -0,0 +1,102 @@
+package objects
+
+import (
+	"fmt"
+	"math"
+)
+
+// Weather описывает погоду миссии: ветер, порывы и турбулентность в облаках.
+// Все величины вычисляются из Seed и Time, поэтому погода воспроизводима.
+type Weather struct {
+	Name           string
+	Seed           int64
+	WindSpeed      float64 // характерная скорость ветра у поверхности
+	JetStreamSpeed float64 // добавка к скорости ветра в реактивном течении
+	GustStrength   float64 // максимальная сила порыва
+	Turbulence     float64 // интенсивность болтанки внутри облачного слоя
+
+	Time       float64 // время, прошедшее с начала миссии
+	CloudDrift float64 // смещение облаков ветром с начала миссии
+}
+
+// Высотные параметры профиля ветра (в игровых единицах высоты)
+const (
+	jetStreamAltitude = 100.0 // высота реактивного течения (~10 км)
+	jetStreamWidth    = 40.0  // полуширина реактивного течения
+	windCeiling       = 500.0 // выше этой высоты ветер практически исчезает
+)
+
+// WeatherPresets - предустановленные варианты погоды для миссий
+var WeatherPresets = []Weather{
+	{Name: "calm", WindSpeed: 0.5, JetStreamSpeed: 5, GustStrength: 0, Turbulence: 1},
+	{Name: "breezy", WindSpeed: 4, JetStreamSpeed: 15, GustStrength: 4, Turbulence: 4},
+	{Name: "stormy", WindSpeed: 9, JetStreamSpeed: 25, GustStrength: 10, Turbulence: 12},
+	{Name: "jetstream", WindSpeed: 2, JetStreamSpeed: 45, GustStrength: 3, Turbulence: 3},
+}
+
+// CurrentWeather - погода текущей миссии
+var CurrentWeather Weather
+
+// NewWeather возвращает копию пресета с указанным именем и зерном
+func NewWeather(preset string, seed int64) (Weather, error) {
+	for _, w := range WeatherPresets {
+		if w.Name == preset {
+			w.Seed = seed
+			return w, nil
+		}
+	}
+	return Weather{}, fmt.Errorf("unknown weather preset %q", preset)
+}
+
+// Reset перезапускает погоду с новым зерном, сохраняя параметры пресета
+func (w *Weather) Reset(seed int64) {
+	w.Seed = seed
+	w.Time = 0
+	w.CloudDrift = 0
+}
+
+// Advance продвигает время погоды и сносит облака ветром на их высоте
+func (w *Weather) Advance(dt float64) {
+	w.Time += dt
+	w.CloudDrift += w.WindAt(float64(CloudBandBottom+CloudBandTop)/2) * dt
+}
+
+// WindAt возвращает горизонтальную скорость ветра на высоте altitude (положительная - вправо)
+func (w *Weather) WindAt(altitude float64) float64 {
+	if altitude >= windCeiling {
+		return 0
+	}
+	// Направление ветра медленно меняется со временем и с высотой
+	direction := valueNoise(w.Seed, w.Time/40+altitude/80, 10)*2 - 1
+
+	// Скорость растёт с высотой и достигает пика в реактивном течении
+	jet := (altitude - jetStreamAltitude) / jetStreamWidth
+	speed := w.WindSpeed*(1+math.Max(altitude, 0)/jetStreamAltitude) + w.JetStreamSpeed*math.Exp(-jet*jet)
+	speed *= 1 - math.Max(altitude, 0)/windCeiling
+
+	return speed*direction + w.gust()
+}
+
+// gust возвращает текущий порыв ветра: короткие всплески поверх среднего ветра
+func (w *Weather) gust() float64 {
+	strength := (valueNoise(w.Seed, w.Time/3, 11) - 0.6) / 0.4
+	if strength <= 0 {
+		return 0
+	}
+	sign := 1.0
+	if valueNoise(w.Seed, w.Time/7, 12) < 0.5 {
+		sign = -1
+	}
+	return sign * strength * w.GustStrength
+}
+
+// TurbulenceAt возвращает случайное ускорение болтанки на высоте altitude.
+// Вне облачного слоя турбулентности нет.
+func (w *Weather) TurbulenceAt(altitude float64) (float64, float64) {
+	if altitude < float64(CloudBandBottom-len(CloudSprite)) || altitude > CloudBandTop {
+		return 0, 0
+	}
+	ax := (valueNoise(w.Seed, w.Time*4, 13)*2 - 1) * w.Turbulence
+	ay := (valueNoise(w.Seed, w.Time*4, 14)*2 - 1) * w.Turbulence
+	return ax, ay
+}
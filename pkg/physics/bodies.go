@@ -0,0 +1,202 @@
+package physics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// RGB - цвет палитры небесного тела
+type RGB struct {
+	R, G, B int32
+}
+
+// SkyStop - опорная точка палитры неба: цвет на заданной высоте
+type SkyStop struct {
+	AltitudeKm float64
+	Color      RGB
+}
+
+// Layer - слой атмосферы, начинающийся на высоте BottomKm
+type Layer struct {
+	Name     string
+	BottomKm float64
+}
+
+// Body описывает небесное тело, с поверхности которого стартует ракета
+type Body struct {
+	Name           string
+	Radius         float64 // радиус в метрах
+	SurfaceGravity float64 // ускорение свободного падения на поверхности (м/с²)
+
+	// Атмосфера
+	SurfaceDensity float64 // плотность у поверхности относительно земной
+	ScaleHeight    float64 // высота однородной атмосферы в метрах
+	KarmanLine     float64 // условная граница космоса в метрах (0 - атмосферы нет)
+	Layers         []Layer // слои атмосферы снизу вверх
+
+	// Палитра
+	Sky          []SkyStop // цвета неба по высоте, от поверхности вверх
+	SurfaceColor RGB       // цвет поверхности рельефа
+	SoilColor    RGB       // цвет грунта под поверхностью
+
+	// Окружение
+	HasClouds     bool
+	HasVegetation bool
+	Obstacles     []objects.ObstacleKind // какие препятствия встречаются у тела
+}
+
+// Встроенные небесные тела
+var (
+	Earth = Body{
+		Name:           "earth",
+		Radius:         EarthRadius,
+		SurfaceGravity: StandardGravity,
+		SurfaceDensity: 1,
+		ScaleHeight:    8500,
+		KarmanLine:     KarmanLine,
+		Layers: []Layer{
+			{"troposphere", 0}, {"stratosphere", 12}, {"mesosphere", 50}, {"thermosphere", 85},
+		},
+		Sky: []SkyStop{
+			{0, RGB{100, 100, 255}},   // тропосфера: от светло-голубого
+			{3.1, RGB{100, 100, 100}}, // ... к более тёмному
+			{12, RGB{100, 100, 100}},
+			{12, RGB{0, 0, 150}}, // стратосфера: от глубокого синего к индиго
+			{50, RGB{0, 0, 100}},
+			{50, RGB{25, 0, 50}}, // мезосфера: тёмно-фиолетовый, переходящий в чёрный
+			{85, RGB{0, 0, 0}},
+		},
+		SurfaceColor:  RGB{0, 128, 0},
+		SoilColor:     RGB{128, 128, 0},
+		HasClouds:     true,
+		HasVegetation: true,
+		Obstacles: []objects.ObstacleKind{
+			objects.ObstacleBird, objects.ObstacleAircraft, objects.ObstacleSatellite, objects.ObstacleDebris,
+		},
+	}
+
+	Moon = Body{
+		Name:           "moon",
+		Radius:         1737400,
+		SurfaceGravity: 1.62,
+		Sky:            []SkyStop{{0, RGB{0, 0, 0}}},
+		SurfaceColor:   RGB{170, 170, 170},
+		SoilColor:      RGB{90, 90, 90},
+		Obstacles:      []objects.ObstacleKind{objects.ObstacleDebris},
+	}
+
+	Mars = Body{
+		Name:           "mars",
+		Radius:         3389500,
+		SurfaceGravity: 3.721,
+		SurfaceDensity: 0.017,
+		ScaleHeight:    11100,
+		KarmanLine:     80000,
+		Layers:         []Layer{{"lower atmosphere", 0}, {"middle atmosphere", 40}, {"upper atmosphere", 60}},
+		Sky: []SkyStop{
+			{0, RGB{200, 150, 110}}, // днём небо Марса цвета ириски
+			{20, RGB{120, 80, 60}},
+			{50, RGB{30, 15, 15}},
+			{80, RGB{0, 0, 0}},
+		},
+		SurfaceColor: RGB{190, 90, 50},
+		SoilColor:    RGB{120, 50, 30},
+		HasClouds:    true,
+		Obstacles:    []objects.ObstacleKind{objects.ObstacleSatellite, objects.ObstacleDebris},
+	}
+)
+
+// Bodies - все встроенные небесные тела
+var Bodies = []*Body{&Earth, &Moon, &Mars}
+
+// CurrentBody - тело, на котором проходит текущая миссия
+var CurrentBody = &Earth
+
+// BodyByName возвращает встроенное тело по имени
+func BodyByName(name string) (*Body, error) {
+	for _, b := range Bodies {
+		if b.Name == name {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown celestial body %q", name)
+}
+
+// Gravity вычисляет гравитацию на высоте altitude (в игровых единицах) по закону обратных квадратов
+func (b *Body) Gravity(altitude float64) float64 {
+	altitudeInMeters := altitude * GameToRealScale
+	return b.SurfaceGravity * math.Pow(b.Radius/(b.Radius+altitudeInMeters), 2)
+}
+
+// HasAtmosphere возвращает true, если у тела есть атмосфера
+func (b *Body) HasAtmosphere() bool {
+	return b.SurfaceDensity > 0
+}
+
+// AirDensity возвращает плотность атмосферы относительно земной у поверхности
+func (b *Body) AirDensity(altitude float64) float64 {
+	if !b.HasAtmosphere() {
+		return 0
+	}
+	altitudeInMeters := math.Max(altitude*GameToRealScale, 0)
+	return b.SurfaceDensity * math.Exp(-altitudeInMeters/b.ScaleHeight)
+}
+
+// InSpace возвращает true, если высота выше границы космоса этого тела.
+// У тела без атмосферы границы нет, и выхода в космос не бывает.
+func (b *Body) InSpace(altitude float64) bool {
+	return b.HasAtmosphere() && altitude*GameToRealScale > b.KarmanLine
+}
+
+// LayerAt возвращает название слоя атмосферы на высоте altitude (в игровых единицах)
+// или пустую строку, если атмосферы нет или ракета в космосе
+func (b *Body) LayerAt(altitude float64) string {
+	if !b.HasAtmosphere() || b.InSpace(altitude) {
+		return ""
+	}
+	altitudeKm := altitude * GameToRealScale / 1000
+	name := ""
+	for _, layer := range b.Layers {
+		if altitudeKm >= layer.BottomKm {
+			name = layer.Name
+		}
+	}
+	return name
+}
+
+// SkyColor возвращает дневной цвет неба на высоте altitudeKm, интерполируя палитру
+func (b *Body) SkyColor(altitudeKm float64) RGB {
+	if len(b.Sky) == 0 {
+		return RGB{}
+	}
+	if altitudeKm <= b.Sky[0].AltitudeKm {
+		return b.Sky[0].Color
+	}
+	for i := 1; i < len(b.Sky); i++ {
+		lo, hi := b.Sky[i-1], b.Sky[i]
+		if altitudeKm < hi.AltitudeKm {
+			t := (altitudeKm - lo.AltitudeKm) / (hi.AltitudeKm - lo.AltitudeKm)
+			return RGB{
+				R: lo.Color.R + int32(float64(hi.Color.R-lo.Color.R)*t),
+				G: lo.Color.G + int32(float64(hi.Color.G-lo.Color.G)*t),
+				B: lo.Color.B + int32(float64(hi.Color.B-lo.Color.B)*t),
+			}
+		}
+	}
+	return b.Sky[len(b.Sky)-1].Color
+}
+
+// ObstacleBands возвращает слои препятствий, встречающихся у тела
+func (b *Body) ObstacleBands() []objects.ObstacleBand {
+	var bands []objects.ObstacleBand
+	for _, band := range objects.ObstacleBands {
+		for _, kind := range b.Obstacles {
+			if band.Kind == kind {
+				bands = append(bands, band)
+			}
+		}
+	}
+	return bands
+}
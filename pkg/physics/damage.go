@@ -0,0 +1,52 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// Параметры нагрева
+const (
+	engineHeatRate    = 20.0  // нагрев в секунду при работе на максимальной тяге
+	maxThrustFraction = 0.95  // доля максимальной тяги, начиная с которой двигатель греется
+	aeroHeatFactor    = 0.004 // коэффициент аэродинамического нагрева (на квадрат скорости)
+	coolingRate       = 0.15  // доля избыточного тепла, отводимая за секунду
+	engineDamageRate  = 0.5   // износ двигателя за градус перегрева в секунду
+	hullDamageRate    = 0.2   // повреждение корпуса за градус перегрева в секунду
+)
+
+// AirDensity возвращает плотность атмосферы текущего тела (1 - у поверхности Земли) на высоте в игровых единицах
+func AirDensity(altitude float64) float64 {
+	return CurrentBody.AirDensity(altitude)
+}
+
+// UpdateHeating нагревает ракету от долгой работы на максимальной тяге и от быстрого полёта
+// в плотной атмосфере; перегрев изнашивает двигатель текущей ступени и корпус.
+// Возвращает true, если корпус разрушен.
+func UpdateHeating(r *objects.Rocket, dt float64, groundLevel int) bool {
+	altitude := float64(groundLevel - r.Y)
+	currentStage := objects.RocketStages[r.ActiveStage]
+
+	heating := 0.0
+	if r.Fuel > 0 && r.ThrustY >= currentStage.MaxThrustY*maxThrustFraction {
+		heating += engineHeatRate
+	}
+	speedSquared := r.Vx*r.Vx + r.Vy*r.Vy
+	aeroHeating := aeroHeatFactor * speedSquared * AirDensity(altitude)
+	heating += aeroHeating
+
+	r.Temperature += (heating - r.Temperature*coolingRate) * dt
+	r.Temperature = math.Max(r.Temperature, 0)
+
+	overheat := r.Temperature - objects.OverheatTemperature
+	if overheat <= 0 {
+		return false
+	}
+	r.DamageEngine(overheat * engineDamageRate * dt)
+	// Корпус страдает, только если греет набегающий поток
+	if aeroHeating > engineHeatRate {
+		return r.DamageHull(overheat * hullDamageRate * dt)
+	}
+	return false
+}
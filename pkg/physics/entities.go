@@ -0,0 +1,101 @@
+package physics
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// SpawnerFactory создаёт спавнер сущностей одного вида для небесного тела body
+type SpawnerFactory func(body *Body) objects.Spawner
+
+// spawnerFactories - виды сущностей, которые появляются в каждом новом мире
+var spawnerFactories []SpawnerFactory
+
+// RegisterSpawner добавляет вид сущностей во все миры, создаваемые NewEntityWorld.
+// Виды регистрируются в init своих пакетов, поэтому новый вид не требует правок в main.
+func RegisterSpawner(factory SpawnerFactory) {
+	spawnerFactories = append(spawnerFactories, factory)
+}
+
+func init() {
+	RegisterSpawner(func(body *Body) objects.Spawner {
+		return objects.ObstacleSpawner(body.ObstacleBands())
+	})
+}
+
+// NewEntityWorld создаёт пустой мир сущностей со спавнерами всех зарегистрированных видов для тела body
+func NewEntityWorld(body *Body) *objects.EntityWorld {
+	w := objects.NewEntityWorld()
+	for _, factory := range spawnerFactories {
+		w.Spawners = append(w.Spawners, factory(body))
+	}
+	return w
+}
+
+// EntityCollision - смертельное столкновение ракеты с сущностью
+type EntityCollision struct {
+	Cause objects.CrashCause
+	Speed float64 // относительная скорость удара
+}
+
+// CollideEntities - система столкновений: проверяет ракету r против сущностей мира w клетка за клеткой.
+// Сущность разрушается, а последствия для ракеты описывает её коллайдер: лёгкий удар повреждает корпус
+// и толкает ракету, а смертельный возвращается вызывающему, чтобы тот начал крушение (nil - ракета цела).
+func CollideEntities(w *objects.EntityWorld, r *objects.Rocket) *EntityCollision {
+	defer w.Flush()
+	for _, e := range w.Colliding(r.X, r.Y, r.GetRocketSprite()) {
+		collider := w.Colliders.Get(e)
+		pos := w.Positions.Get(e)
+		var vel objects.Velocity
+		if v := w.Velocities.Get(e); v != nil {
+			vel = *v
+		}
+
+		relativeSpeed := math.Hypot(r.Vx-vel.Vx, r.Vy-vel.Vy)
+		w.Destroy(e)
+		objects.DebrisEmitter.Burst(pos.X, pos.Y, 0, -1, vel.Vx, vel.Vy, 8)
+
+		if collider.Fatal(relativeSpeed) || r.DamageHull(collider.Damage) {
+			return &EntityCollision{Cause: collider.Cause, Speed: relativeSpeed}
+		}
+		// Лёгкий удар: ракету толкает в сторону движения сущности
+		r.Vx += (vel.Vx - r.Vx) * collider.Push
+		r.Vy += (vel.Vy - r.Vy) * collider.Push
+	}
+	return nil
+}
+
+// UpdateEntities - система движения: продвигает все сущности мира w по их компонентам
+func UpdateEntities(w *objects.EntityWorld, dt float64) {
+	w.Lifetimes.Each(func(e objects.Entity, life *objects.Lifetime) {
+		life.Age += dt
+		if life.MaxAge > 0 && life.Age >= life.MaxAge {
+			w.Destroy(e)
+		}
+	})
+
+	w.Wanders.Each(func(e objects.Entity, wander *objects.Wander) {
+		if vel := w.Velocities.Get(e); vel != nil && rand.Float64() < wander.TurnRate*dt {
+			vel.Vx = -vel.Vx
+		}
+	})
+
+	w.Velocities.Each(func(e objects.Entity, vel *objects.Velocity) {
+		if pos := w.Positions.Get(e); pos != nil {
+			pos.X += vel.Vx * dt
+			pos.Y += vel.Vy * dt
+		}
+	})
+
+	w.Oscillations.Each(func(e objects.Entity, osc *objects.Oscillation) {
+		pos, life := w.Positions.Get(e), w.Lifetimes.Get(e)
+		if pos == nil || life == nil {
+			return
+		}
+		pos.Y = osc.BaseY + math.Sin(life.Age*osc.Frequency)*osc.Amplitude
+	})
+
+	w.Flush()
+}
@@ -0,0 +1,47 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// spawnAtNose создаёт в w сущность с коллайдером c у носа ракеты в клетке (0, 0)
+func spawnAtNose(w *objects.EntityWorld, c objects.Collider, vx float64) objects.Entity {
+	e := w.Spawn()
+	w.Positions.Set(e, objects.Position{X: 2, Y: 0})
+	w.Velocities.Set(e, objects.Velocity{Vx: vx})
+	w.Sprites.Set(e, objects.Sprite{Frames: [][]string{{"x"}}})
+	w.Colliders.Set(e, c)
+	return e
+}
+
+func TestCollideEntities(t *testing.T) {
+	defer func(particles []objects.Particle) { objects.Particles = particles }(objects.Particles)
+
+	w := objects.NewEntityWorld()
+	r := &objects.Rocket{}
+	r.ResetDamage()
+	spawnAtNose(w, objects.Collider{Cause: objects.CrashStructuralFailure, FatalSpeed: -1, Damage: 10, Push: 0.5}, 4)
+	if hit := CollideEntities(w, r); hit != nil {
+		t.Fatalf("light collision crashed the rocket: %+v", *hit)
+	}
+	if r.Hull != objects.MaxHull-10 || r.Vx != 2 {
+		t.Errorf("after a light collision hull %.0f, vx %.1f, want %.0f and 2", r.Hull, r.Vx, objects.MaxHull-10)
+	}
+	if w.Colliders.Len() != 0 {
+		t.Errorf("%d entities left after the collision, want 0", w.Colliders.Len())
+	}
+
+	spawnAtNose(w, objects.Collider{Cause: objects.CrashAircraft}, -20)
+	hit := CollideEntities(w, r)
+	if hit == nil || hit.Cause != objects.CrashAircraft || hit.Speed != 22 {
+		t.Fatalf("fatal collision = %+v, want %s at 22", hit, objects.CrashAircraft)
+	}
+
+	e := spawnAtNose(w, objects.Collider{Cause: objects.CrashAircraft}, 0)
+	w.Positions.Set(e, objects.Position{X: 0, Y: 0}) // в пустой клетке спрайта ракеты
+	if hit := CollideEntities(w, r); hit != nil {
+		t.Errorf("entity in an empty sprite cell collided: %+v", *hit)
+	}
+}
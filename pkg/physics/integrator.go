@@ -0,0 +1,98 @@
+package physics
+
+import "fmt"
+
+// State - положение и скорость тела в мировых координатах (Y растёт вниз)
+type State struct {
+	X, Y   float64
+	Vx, Vy float64
+}
+
+// AccelFunc возвращает ускорение тела в состоянии s
+type AccelFunc func(s State) (ax, ay float64)
+
+// Integrator продвигает состояние на шаг dt
+type Integrator struct {
+	Name string
+	Step func(s State, accel AccelFunc, dt float64) State
+}
+
+// Численные методы интегрирования движения
+var (
+	// Euler - явный метод Эйлера: позиция обновляется по старой скорости. Первый порядок, энергия растёт.
+	Euler = &Integrator{Name: "euler", Step: stepEuler}
+	// SemiImplicitEuler - полунеявный (симплектический) Эйлер: позиция обновляется по новой скорости
+	SemiImplicitEuler = &Integrator{Name: "symplectic", Step: stepSemiImplicitEuler}
+	// Verlet - скоростной Верле: второй порядок, энергия не дрейфует в консервативных полях
+	Verlet = &Integrator{Name: "verlet", Step: stepVerlet}
+	// RK4 - классический метод Рунге-Кутты четвёртого порядка
+	RK4 = &Integrator{Name: "rk4", Step: stepRK4}
+)
+
+// Integrators - все доступные методы интегрирования
+var Integrators = []*Integrator{Euler, SemiImplicitEuler, Verlet, RK4}
+
+// CurrentIntegrator - метод, которым UpdateRocket интегрирует движение ракеты
+var CurrentIntegrator = SemiImplicitEuler
+
+// IntegratorByName возвращает метод интегрирования по имени
+func IntegratorByName(name string) (*Integrator, error) {
+	for _, integ := range Integrators {
+		if integ.Name == name {
+			return integ, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown integrator %q", name)
+}
+
+func stepEuler(s State, accel AccelFunc, dt float64) State {
+	ax, ay := accel(s)
+	return State{
+		X:  s.X + s.Vx*dt,
+		Y:  s.Y + s.Vy*dt,
+		Vx: s.Vx + ax*dt,
+		Vy: s.Vy + ay*dt,
+	}
+}
+
+func stepSemiImplicitEuler(s State, accel AccelFunc, dt float64) State {
+	ax, ay := accel(s)
+	vx, vy := s.Vx+ax*dt, s.Vy+ay*dt
+	return State{X: s.X + vx*dt, Y: s.Y + vy*dt, Vx: vx, Vy: vy}
+}
+
+func stepVerlet(s State, accel AccelFunc, dt float64) State {
+	ax, ay := accel(s)
+	next := State{
+		X: s.X + s.Vx*dt + ax*dt*dt/2,
+		Y: s.Y + s.Vy*dt + ay*dt*dt/2,
+		// Прогноз скорости нужен, только если ускорение зависит от скорости
+		Vx: s.Vx + ax*dt,
+		Vy: s.Vy + ay*dt,
+	}
+	nax, nay := accel(next)
+	next.Vx = s.Vx + (ax+nax)*dt/2
+	next.Vy = s.Vy + (ay+nay)*dt/2
+	return next
+}
+
+func stepRK4(s State, accel AccelFunc, dt float64) State {
+	// derivative возвращает производную состояния: (скорость, ускорение)
+	derivative := func(s State) State {
+		ax, ay := accel(s)
+		return State{X: s.Vx, Y: s.Vy, Vx: ax, Vy: ay}
+	}
+	advance := func(s, d State, h float64) State {
+		return State{X: s.X + d.X*h, Y: s.Y + d.Y*h, Vx: s.Vx + d.Vx*h, Vy: s.Vy + d.Vy*h}
+	}
+	k1 := derivative(s)
+	k2 := derivative(advance(s, k1, dt/2))
+	k3 := derivative(advance(s, k2, dt/2))
+	k4 := derivative(advance(s, k3, dt))
+	return State{
+		X:  s.X + (k1.X+2*k2.X+2*k3.X+k4.X)*dt/6,
+		Y:  s.Y + (k1.Y+2*k2.Y+2*k3.Y+k4.Y)*dt/6,
+		Vx: s.Vx + (k1.Vx+2*k2.Vx+2*k3.Vx+k4.Vx)*dt/6,
+		Vy: s.Vy + (k1.Vy+2*k2.Vy+2*k3.Vy+k4.Vy)*dt/6,
+	}
+}
@@ -0,0 +1,82 @@
+package physics
+
+import (
+	"math"
+	"testing"
+)
+
+// integrate продвигает состояние s методом integ на n шагов dt
+func integrate(integ *Integrator, s State, accel AccelFunc, dt float64, n int) State {
+	for i := 0; i < n; i++ {
+		s = integ.Step(s, accel, dt)
+	}
+	return s
+}
+
+func TestIntegratorsConstantAcceleration(t *testing.T) {
+	const dt, steps = 1.0 / 30, 300
+	const vx0, vy0 = 2.0, -5.0
+	tt := dt * steps
+	// Свободное падение и подъём на тяге, превышающей тяжесть (Y растёт вниз)
+	for _, a := range []float64{Earth.SurfaceGravity, Earth.SurfaceGravity - 20} {
+		accel := func(State) (float64, float64) { return 0.5, a }
+		wantX := vx0*tt + 0.5*tt*tt/2
+		wantY := vy0*tt + a*tt*tt/2
+		for _, integ := range Integrators {
+			s := integrate(integ, State{Vx: vx0, Vy: vy0}, accel, dt, steps)
+			// Методы второго порядка и выше точны при постоянном ускорении,
+			// у методов Эйлера ошибка положения равна a*t*dt/2
+			tolerance := 1e-9
+			if integ == Euler || integ == SemiImplicitEuler {
+				tolerance = math.Abs(a)*tt*dt/2 + 1e-9
+			}
+			if err := math.Abs(s.Y - wantY); err > tolerance {
+				t.Errorf("%s, a=%.2f: y=%.6f, want %.6f (error %.2e > %.2e)", integ.Name, a, s.Y, wantY, err, tolerance)
+			}
+			if err := math.Abs(s.X - wantX); err > 0.5*tt*dt/2+1e-9 {
+				t.Errorf("%s, a=%.2f: x=%.6f, want %.6f", integ.Name, a, s.X, wantX)
+			}
+			if want := vy0 + a*tt; math.Abs(s.Vy-want) > 1e-9 {
+				t.Errorf("%s, a=%.2f: vy=%.6f, want %.6f", integ.Name, a, s.Vy, want)
+			}
+		}
+	}
+}
+
+func TestIntegratorsEnergyDrift(t *testing.T) {
+	// Гармонический осциллятор: полная энергия y²/2 + v²/2 должна сохраняться
+	accel := func(s State) (float64, float64) { return 0, -s.Y }
+	energy := func(s State) float64 { return (s.Y*s.Y + s.Vy*s.Vy) / 2 }
+	start := State{Y: 1}
+	tests := []struct {
+		integ    *Integrator
+		maxDrift float64 // наибольший допустимый модуль дрейфа; 0 - энергия обязана расти
+	}{
+		{Euler, 0},
+		{SemiImplicitEuler, 1e-2},
+		{Verlet, 1e-2},
+		{RK4, 1e-4},
+	}
+	for _, tc := range tests {
+		drift := energy(integrate(tc.integ, start, accel, 0.1, 3000)) - energy(start)
+		switch {
+		case tc.maxDrift == 0 && drift < 1:
+			t.Errorf("%s: energy drift %.2e, want the explicit method to gain energy", tc.integ.Name, drift)
+		case tc.maxDrift > 0 && math.Abs(drift) > tc.maxDrift:
+			t.Errorf("%s: energy drift %.2e, want below %.0e", tc.integ.Name, drift, tc.maxDrift)
+		}
+	}
+}
+
+func TestIntegratorsFreeFallEnergy(t *testing.T) {
+	// В однородном поле v²/2 - a*y сохраняется у методов второго порядка точно
+	const a, dt = 9.81, 1.0 / 30
+	accel := func(State) (float64, float64) { return 0, a }
+	energy := func(s State) float64 { return s.Vy*s.Vy/2 - a*s.Y }
+	start := State{Vy: -5}
+	for _, integ := range []*Integrator{Verlet, RK4} {
+		if drift := energy(integrate(integ, start, accel, dt, 300)) - energy(start); math.Abs(drift) > 1e-8 {
+			t.Errorf("%s: energy drift %.2e in a uniform field", integ.Name, drift)
+		}
+	}
+}
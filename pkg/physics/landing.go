@@ -0,0 +1,39 @@
+package physics
+
+import "github.com/shameoff/rocket-in-console/pkg/objects"
+
+// Пределы безопасной посадки
+const (
+	SafeLandingSpeed  = 20.0 // наибольшая скорость касания, которую выдерживают опоры
+	MaxLandingSlope   = 1    // допустимый перепад рельефа под опорой; больше - ракета опрокидывается
+	LandingDamageRate = 2.0  // повреждение корпуса на единицу скорости жёсткого, но допустимого касания
+)
+
+// Landing - исход касания поверхности
+type Landing struct {
+	Crashed bool
+	Cause   objects.CrashCause
+	Damage  float64 // повреждение корпуса от жёсткого, но допустимого касания
+}
+
+// JudgeLanding решает, чем кончится касание поверхности со скоростью speed: посадкой или крушением.
+// onSide - удар о склон сбоку, slope - перепад рельефа под опорой, hull - прочность корпуса до касания.
+// По этим правилам разбивается ракета в игре и окрашивается место касания в прогнозе траектории.
+func JudgeLanding(speed float64, onSide bool, slope int, hull float64) Landing {
+	switch {
+	case onSide && speed > SafeLandingSpeed/4:
+		return Landing{Crashed: true, Cause: objects.CrashTerrain}
+	case speed > SafeLandingSpeed:
+		return Landing{Crashed: true, Cause: objects.CrashHardLanding}
+	case speed > 0 && slope > MaxLandingSlope:
+		return Landing{Crashed: true, Cause: objects.CrashTippedOver}
+	case speed > SafeLandingSpeed/2:
+		// Жёсткое, но допустимое касание повреждает корпус
+		damage := (speed - SafeLandingSpeed/2) * LandingDamageRate
+		if hull-damage <= 0 {
+			return Landing{Crashed: true, Cause: objects.CrashStructuralFailure, Damage: damage}
+		}
+		return Landing{Damage: damage}
+	}
+	return Landing{}
+}
@@ -0,0 +1,84 @@
+package physics
+
+import (
+	"testing"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+func TestJudgeLanding(t *testing.T) {
+	tests := []struct {
+		name   string
+		speed  float64
+		onSide bool
+		slope  int
+		hull   float64
+		want   Landing
+	}{
+		{"soft", 5, false, 0, objects.MaxHull, Landing{}},
+		{"hard", SafeLandingSpeed + 1, false, 0, objects.MaxHull, Landing{Crashed: true, Cause: objects.CrashHardLanding}},
+		{"slow on a slope", 2, false, MaxLandingSlope + 1, objects.MaxHull, Landing{Crashed: true, Cause: objects.CrashTippedOver}},
+		{"slow side hit", SafeLandingSpeed / 4, true, 0, objects.MaxHull, Landing{}},
+		{"side hit", SafeLandingSpeed/4 + 1, true, 0, objects.MaxHull, Landing{Crashed: true, Cause: objects.CrashTerrain}},
+		{"firm", SafeLandingSpeed/2 + 2, false, 0, objects.MaxHull, Landing{Damage: 2 * LandingDamageRate}},
+		{"firm on a damaged hull", SafeLandingSpeed/2 + 2, false, 0, 2 * LandingDamageRate,
+			Landing{Crashed: true, Cause: objects.CrashStructuralFailure, Damage: 2 * LandingDamageRate}},
+	}
+	for _, tc := range tests {
+		if got := JudgeLanding(tc.speed, tc.onSide, tc.slope, tc.hull); got != tc.want {
+			t.Errorf("%s: JudgeLanding(%.1f, %v, %d, %.0f) = %+v, want %+v", tc.name, tc.speed, tc.onSide, tc.slope, tc.hull, got, tc.want)
+		}
+	}
+}
+
+// slopeColumn возвращает колонку рельефа, опора над которой шире допустимого перепада
+func slopeColumn(t *testing.T, sprite []string) int {
+	t.Helper()
+	for x := objects.Ground.LaunchX + objects.Ground.PadWidth; x < objects.Ground.LaunchX+objects.Ground.PadSpacing; x++ {
+		if FootprintSlope(x, sprite, &objects.Ground) > MaxLandingSlope {
+			return x
+		}
+	}
+	t.Fatal("no sloped terrain near the launch pad")
+	return 0
+}
+
+func TestPredictionLanding(t *testing.T) {
+	defer func(ground objects.Terrain) { objects.Ground = ground }(objects.Ground)
+	objects.InitTerrain(1)
+	sprite := objects.RocketSprite
+	tests := []struct {
+		name string
+		x    int
+		vy   float64
+		hull float64
+		want Landing
+	}{
+		{"pad", objects.Ground.LaunchX, 3, objects.MaxHull, Landing{}},
+		{"slope", slopeColumn(t, sprite), 3, objects.MaxHull, Landing{Crashed: true, Cause: objects.CrashTippedOver}},
+		{"damaged hull", objects.Ground.LaunchX, 15, 1, Landing{Crashed: true, Cause: objects.CrashStructuralFailure}},
+	}
+	for _, tc := range tests {
+		r := &objects.Rocket{Fuel: 10000, Vy: tc.vy}
+		r.ResetDamage()
+		r.Hull = tc.hull
+		r.SetPosition(float64(tc.x), float64(objects.Ground.SurfaceAt(tc.x)-len(sprite))-2)
+
+		p := PredictTrajectory(r, objects.GroundLevel, 0, 5)
+		if !p.Impact {
+			t.Fatalf("%s: no impact predicted", tc.name)
+		}
+		got := p.Landing()
+		if got.Crashed != tc.want.Crashed || got.Cause != tc.want.Cause {
+			t.Errorf("%s: predicted %+v at %.1f, want %+v", tc.name, got, p.ImpactSpeed, tc.want)
+		}
+
+		// Прогноз обязан совпасть с тем, что случится с самой ракетой
+		for i := 0; i < 1000 && r.ImpactSpeed == 0 && !r.TerrainCollision; i++ {
+			UpdateRocket(r, predictionStep, objects.GroundLevel, 0)
+		}
+		if actual := JudgeLanding(r.ImpactSpeed, r.TerrainCollision, r.GroundSlope, r.Hull); actual != got {
+			t.Errorf("%s: predicted %+v, the flight ended with %+v", tc.name, got, actual)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package physics
+
+import (
+	"github.com/shameoff/rocket-in-console/pkg/events"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+const (
+	// CosmicSpeedThreshold - скорость падения, начиная с которой ракета летит "космически" быстро
+	CosmicSpeedThreshold = 100.0
+	// airborneHeight - высота над рельефом, после которой ракета считается взлетевшей
+	airborneHeight = 0.5
+)
+
+// FlightMonitor сравнивает состояние ракеты с предыдущим шагом и публикует события полёта
+type FlightMonitor struct {
+	stage    int
+	airborne bool
+	inSpace  bool
+	cosmic   bool
+	layer    string
+}
+
+// Reset запоминает исходное состояние ракеты, чтобы не публиковать события о нём
+func (m *FlightMonitor) Reset(r *objects.Rocket, groundLevel int) {
+	_, y := r.Position()
+	m.stage = r.ActiveStage
+	m.airborne = HeightAboveTerrain(r) > airborneHeight
+	m.inSpace = CurrentBody.InSpace(float64(groundLevel) - y)
+	m.cosmic = r.Vy > CosmicSpeedThreshold
+	m.layer = CurrentBody.LayerAt(float64(groundLevel) - y)
+}
+
+// Update публикует в bus события, произошедшие за последний шаг полёта на момент t
+func (m *FlightMonitor) Update(r *objects.Rocket, groundLevel int, t float64, bus *events.Bus) {
+	if r.ActiveStage != m.stage {
+		bus.Publish(events.StageChanged{Time: t, From: m.stage, To: r.ActiveStage})
+		m.stage = r.ActiveStage
+	}
+
+	if HeightAboveTerrain(r) > airborneHeight {
+		if !m.airborne {
+			bus.Publish(events.Liftoff{Time: t})
+		}
+		m.airborne = true
+	} else if m.airborne && r.ImpactSpeed > 0 {
+		bus.Publish(events.Landed{Time: t, Speed: r.ImpactSpeed, OnPad: objects.Ground.IsPad(r.X + len(r.GetRocketSprite()[0])/2)})
+		m.airborne = false
+	}
+
+	_, y := r.Position()
+	altitude := float64(groundLevel) - y
+	if inSpace := CurrentBody.InSpace(altitude); inSpace != m.inSpace {
+		bus.Publish(events.KarmanCrossed{Time: t, Ascending: inSpace})
+		m.inSpace = inSpace
+	}
+	if layer := CurrentBody.LayerAt(altitude); layer != m.layer {
+		// Выход в космос и возвращение с орбиты уже описывает KarmanCrossed
+		if layer != "" && m.layer != "" {
+			bus.Publish(events.AtmosphereLayer{Time: t, Name: layer, Ascending: layerIndex(layer) > layerIndex(m.layer)})
+		}
+		m.layer = layer
+	}
+
+	if cosmic := r.Vy > CosmicSpeedThreshold; cosmic != m.cosmic {
+		bus.Publish(events.CosmicSpeed{Time: t, Speed: r.Vy, Reached: cosmic})
+		m.cosmic = cosmic
+	}
+}
+
+// layerIndex возвращает номер слоя атмосферы текущего тела по названию
+func layerIndex(name string) int {
+	for i, layer := range CurrentBody.Layers {
+		if layer.Name == name {
+			return i
+		}
+	}
+	return -1
+}
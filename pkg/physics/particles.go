@@ -0,0 +1,90 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// Порог тяги, ниже которого двигатели считаются выключенными
+const exhaustThreshold = 0.5
+
+// UpdateParticles продвигает все частицы на шаг dt и удаляет погибшие.
+func UpdateParticles(dt float64, groundLevel int) {
+	alive := objects.Particles[:0]
+	for _, p := range objects.Particles {
+		p.Life -= dt
+		if p.Life <= 0 {
+			continue
+		}
+
+		switch p.Kind {
+		case objects.ParticleSmoke:
+			// Дым быстро теряет скорость и медленно поднимается
+			p.Vx *= math.Pow(0.2, dt)
+			p.Vy *= math.Pow(0.2, dt)
+			p.Vy -= 0.5 * dt
+		case objects.ParticleDebris:
+			// Обломки падают под действием гравитации
+			p.Vy += CalculateGravity(float64(groundLevel)-p.Y) * dt
+		}
+
+		p.X += p.Vx * dt
+		p.Y += p.Vy * dt
+
+		// Частицы не проваливаются сквозь рельеф
+		surface := float64(objects.Ground.SurfaceAt(int(math.Floor(p.X))))
+		if p.Y >= surface {
+			p.Y = surface - 0.01
+			p.Vy = 0
+			p.Vx *= 0.5
+		}
+		alive = append(alive, p)
+	}
+	objects.Particles = alive
+}
+
+// EmitExhaust выпускает частицы из сопел ракеты пропорционально текущей тяге.
+func EmitExhaust(r *objects.Rocket, dt float64, groundLevel int) {
+	rocketSprite := r.GetRocketSprite()
+	width := float64(len(rocketSprite[0]))
+	height := float64(len(rocketSprite))
+	x := float64(r.X)
+	y := float64(r.Y)
+
+	altitude := float64(groundLevel - r.Y)
+	gravity := CalculateGravity(altitude)
+	currentStage := objects.RocketStages[r.ActiveStage]
+
+	// Главный двигатель: факел вниз и дымовой след
+	if r.ThrustY > gravity+exhaustThreshold && r.Fuel > 0 {
+		throttle := (r.ThrustY - gravity) / currentStage.MaxThrustY
+		nozzleY := y + height
+		for _, nozzleX := range []float64{x + width/3, x + 2*width/3} {
+			objects.EngineEmitter.Emit(nozzleX, nozzleY, 0, 1, r.Vx, r.Vy, throttle, dt)
+			// Дым оставляем только в атмосфере
+			if CurrentBody.HasAtmosphere() && !CurrentBody.InSpace(altitude) {
+				objects.SmokeEmitter.Emit(nozzleX, nozzleY+1, 0, 1, 0, 0, throttle, dt)
+			}
+		}
+	} else if r.ThrustY < gravity-exhaustThreshold {
+		// Обратная тяга - струя вверх из верхних дюз
+		throttle := (gravity - r.ThrustY) / currentStage.MaxThrustY
+		for _, nozzleX := range []float64{x + width/3, x + 2*width/3} {
+			objects.ThrusterEmitter.Emit(nozzleX, y-1, 0, -1, r.Vx, r.Vy, throttle, dt)
+		}
+	}
+
+	// Вспомогательные двигатели: струя в сторону, противоположную тяге
+	if math.Abs(r.ThrustX) > exhaustThreshold {
+		throttle := math.Abs(r.ThrustX) / currentStage.MaxThrustX
+		dirX := -1.0
+		nozzleX := x - 1
+		if r.ThrustX < 0 {
+			dirX = 1.0
+			nozzleX = x + width
+		}
+		objects.ThrusterEmitter.Emit(nozzleX, y+height/3, dirX, 0, r.Vx, r.Vy, throttle, dt)
+		objects.ThrusterEmitter.Emit(nozzleX, y+2*height/3, dirX, 0, r.Vx, r.Vy, throttle, dt)
+	}
+}
@@ -18,26 +18,37 @@ const KarmanLine = 100000.0
 // Масштабный коэффициент для перевода игровых единиц в реальные
 const GameToRealScale = 100.0
 
-// Calculates gravity strength at given altitude using inverse square law
+// Calculates gravity strength of the current body at given altitude using inverse square law
+// g = GM/r² = g₀*(R/(R+h))², where g₀ is surface gravity, R is body radius, h is altitude
 func CalculateGravity(altitude float64) float64 {
-	// Convert game altitude units to meters
-	altitudeInMeters := altitude * GameToRealScale
-
-	// Calculate gravity using inverse square law
-	// g = GM/r² = g₀*(R/(R+h))²
-	// where g₀ is standard gravity, R is Earth radius, h is altitude
-	gravity := StandardGravity * math.Pow(EarthRadius/(EarthRadius+altitudeInMeters), 2)
-	return gravity
+	return CurrentBody.Gravity(altitude)
+}
+
+// VerticalThrustGain возвращает ускорение на единицу вертикальной тяги для ступени stage.
+// Текущая ступень определяет эффективность тяги, повреждения двигателя её снижают.
+func VerticalThrustGain(r *objects.Rocket, stage int) float64 {
+	// Нормализуем относительно базовой ступени
+	return objects.RocketStages[stage].MaxThrustY / 15.0 * r.StageThrustEfficiency(stage)
+}
+
+// HorizontalThrustGain возвращает ускорение на единицу горизонтальной тяги для ступени stage.
+// Повреждения корпуса снижают манёвренность.
+func HorizontalThrustGain(r *objects.Rocket, stage int) float64 {
+	// Нормализуем относительно базовой ступени
+	return objects.RocketStages[stage].MaxThrustX / 2.0 * r.SteeringEfficiency()
 }
 
 // UpdateRocket обновляет состояние ракеты с учётом реалистичной гравитации и характеристик текущей ступени.
+// Движение интегрируется методом CurrentIntegrator.
 func UpdateRocket(r *objects.Rocket, dt float64, groundLevel int, hoverThrust float64) {
+	posX, posY := r.Position()
+
 	// Вычисляем "альтитуду" (расстояние от земли)
-	altitude := float64(groundLevel - r.Y)
+	altitude := float64(groundLevel) - posY
 
 	// Рассчитываем силу гравитации на текущей высоте
 	gravity := CalculateGravity(altitude)
-	
+
 	// Получаем текущую ступень и её характеристики
 	currentStage := objects.RocketStages[r.ActiveStage]
 
@@ -51,17 +62,16 @@ func UpdateRocket(r *objects.Rocket, dt float64, groundLevel int, hoverThrust fl
 		}
 	}
 
-	// Вычисляем модификаторы ускорения на основе характеристик ступени
-	// Текущая ступень определяет эффективность тяги
-	thrustEfficiencyY := currentStage.MaxThrustY / 15.0 // Нормализуем относительно базовой ступени
-	thrustEfficiencyX := currentStage.MaxThrustX / 2.0  // Нормализуем относительно базовой ступени
-	
+	// Вычисляем модификаторы ускорения на основе характеристик ступени и повреждений
+	thrustEfficiencyY := VerticalThrustGain(r, r.ActiveStage)
+	thrustEfficiencyX := HorizontalThrustGain(r, r.ActiveStage)
+
 	// Ограничиваем тягу максимальной для текущей ступени
 	appliedThrustY := r.ThrustY
 	if appliedThrustY > currentStage.MaxThrustY {
 		appliedThrustY = currentStage.MaxThrustY
 	}
-	
+
 	appliedThrustX := r.ThrustX
 	if math.Abs(appliedThrustX) > currentStage.MaxThrustX {
 		if appliedThrustX > 0 {
@@ -71,41 +81,40 @@ func UpdateRocket(r *objects.Rocket, dt float64, groundLevel int, hoverThrust fl
 		}
 	}
 
-	// Вычисляем чистое ускорение с учетом эффективности ступени
-	netAccY := appliedThrustY*thrustEfficiencyY - gravity
-	netAccX := appliedThrustX * thrustEfficiencyX
-
-	// Интегрируем ускорение в скорость
+	// Ускорение с учетом эффективности ступени; гравитация пересчитывается по высоте
+	// в каждой точке, которую запрашивает интегратор.
 	// Отрицательная Vy означает движение вверх, положительная - вниз
-	r.Vy -= netAccY * dt
-	r.Vx += netAccX * dt
-
-	// Аккумулируем дробные значения перемещения
-	r.AccumulatedX += r.Vx * dt
-	r.AccumulatedY += r.Vy * dt
-
-	// Перемещаем ракету только когда накопленное смещение >= 1 пиксель
-	deltaX := int(r.AccumulatedX)
-	deltaY := int(r.AccumulatedY)
-
-	if deltaX != 0 {
-		r.X += deltaX
-		r.AccumulatedX -= float64(deltaX)
-	}
-
-	if deltaY != 0 {
-		r.Y += deltaY
-		r.AccumulatedY -= float64(deltaY)
+	accel := func(s State) (float64, float64) {
+		return appliedThrustX * thrustEfficiencyX, CalculateGravity(float64(groundLevel)-s.Y) - appliedThrustY*thrustEfficiencyY
 	}
+	next := CurrentIntegrator.Step(State{X: posX, Y: posY, Vx: r.Vx, Vy: r.Vy}, accel, dt)
+	r.Vx, r.Vy = next.Vx, next.Vy
+	prevY := r.Y
+	r.SetPosition(next.X, next.Y)
+	deltaY := r.Y - prevY
 
 	// Получаем актуальный спрайт ракеты для проверки столкновений
 	rocketSprite := r.GetRocketSprite()
 
-	// Проверка на касание земли
-	if r.Y+len(rocketSprite) > groundLevel && r.Vy >= 0 {
-		r.Y = groundLevel - len(rocketSprite)
-		r.Vy = 0
-		r.AccumulatedY = 0
+	// Проверка на касание рельефа под каждой клеткой спрайта
+	r.ImpactSpeed = 0
+	r.GroundSlope = 0
+	r.TerrainCollision = false
+	if penetration := TerrainPenetration(r.X, r.Y, rocketSprite, &objects.Ground); penetration > 0 {
+		// Если ракета утоплена глубже, чем объясняется вертикальным смещением за шаг,
+		// значит она врезалась в склон сбоку
+		if penetration > max(deltaY, 0)+maxStepUp {
+			r.TerrainCollision = true
+			r.ImpactSpeed = math.Hypot(r.Vx, r.Vy)
+		} else if r.Vy > 0 {
+			r.ImpactSpeed = r.Vy
+		}
+		// Ракета встаёт ровно на поверхность
+		r.SetPosition(r.PosX, float64(r.Y-penetration))
+		if r.Vy > 0 {
+			r.Vy = 0
+		}
+		r.GroundSlope = FootprintSlope(r.X, rocketSprite, &objects.Ground)
 	}
 
 	// Затухание горизонтальной скорости
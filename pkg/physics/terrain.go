@@ -0,0 +1,60 @@
+package physics
+
+import "github.com/shameoff/rocket-in-console/pkg/objects"
+
+// Максимальная ступенька рельефа, на которую ракета "въезжает" без удара
+const maxStepUp = 1
+
+// HeightAboveTerrain возвращает расстояние от низа спрайта до самой высокой точки рельефа под ракетой
+func HeightAboveTerrain(r *objects.Rocket) float64 {
+	sprite := r.GetRocketSprite()
+	surface := objects.Ground.SurfaceAt(r.X)
+	for dx := 1; dx < len(sprite[0]); dx++ {
+		surface = min(surface, objects.Ground.SurfaceAt(r.X+dx))
+	}
+	_, y := r.Position()
+	return float64(surface-len(sprite)) - y
+}
+
+// TerrainPenetration возвращает, на сколько строк спрайт в позиции (x, y) утоплен в рельеф.
+// Проверяется каждая непустая клетка спрайта относительно поверхности в её колонке.
+func TerrainPenetration(x, y int, sprite []string, terrain *objects.Terrain) int {
+	penetration := 0
+	for dy, line := range sprite {
+		for dx, ch := range []rune(line) {
+			if ch == ' ' {
+				continue
+			}
+			depth := y + dy - terrain.SurfaceAt(x+dx) + 1
+			if depth > penetration {
+				penetration = depth
+			}
+		}
+	}
+	return penetration
+}
+
+// FootprintSlope возвращает перепад высот рельефа под опорой спрайта:
+// разницу между самой высокой и самой низкой поверхностью под непустыми колонками нижней строки.
+func FootprintSlope(x int, sprite []string, terrain *objects.Terrain) int {
+	if len(sprite) == 0 {
+		return 0
+	}
+	bottom := []rune(sprite[len(sprite)-1])
+	minY, maxY := 0, 0
+	found := false
+	for dx, ch := range bottom {
+		if ch == ' ' {
+			continue
+		}
+		surface := terrain.SurfaceAt(x + dx)
+		if !found || surface < minY {
+			minY = surface
+		}
+		if !found || surface > maxY {
+			maxY = surface
+		}
+		found = true
+	}
+	return maxY - minY
+}
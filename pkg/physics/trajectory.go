@@ -0,0 +1,77 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// Параметры предсказания траектории
+const (
+	predictionStep        = 0.05 // шаг прогноза в секундах
+	predictionDotInterval = 0.25 // через сколько секунд полёта ставится очередная точка траектории
+)
+
+// TrajectoryPoint - предсказанное положение центра ракеты
+type TrajectoryPoint struct {
+	X, Y float64
+	T    float64 // секунды от текущего момента
+}
+
+// Prediction - прогноз полёта ракеты при неизменной тяге
+type Prediction struct {
+	Points []TrajectoryPoint
+
+	Impact       bool    // ракета коснётся поверхности в пределах горизонта прогноза
+	ImpactX      float64 // центр ракеты в момент касания
+	ImpactY      float64
+	ImpactTime   float64 // секунды до касания
+	ImpactSpeed  float64 // скорость касания
+	ImpactOnSide bool    // удар о склон сбоку
+	ImpactSlope  int     // перепад рельефа под опорой при касании
+	ImpactHull   float64 // прочность корпуса перед касанием
+}
+
+// Landing возвращает исход предсказанного касания по тем же правилам, что и в игре
+func (p *Prediction) Landing() Landing {
+	return JudgeLanding(p.ImpactSpeed, p.ImpactOnSide, p.ImpactSlope, p.ImpactHull)
+}
+
+// PredictTrajectory прогоняет UpdateRocket на копии ракеты на horizon секунд вперёд
+// с текущей тягой и возвращает точки траектории и место касания поверхности.
+// Ветер и турбулентность не учитываются. Для стоящей на поверхности ракеты прогноз пустой.
+func PredictTrajectory(r *objects.Rocket, groundLevel int, hoverThrust, horizon float64) Prediction {
+	var p Prediction
+	if horizon <= 0 || HeightAboveTerrain(r) <= airborneHeight {
+		return p
+	}
+
+	ghost := *r
+	ghost.EngineHealth = append([]float64(nil), r.EngineHealth...)
+	sprite := ghost.GetRocketSprite()
+	centerX, centerY := float64(len(sprite[0]))/2, float64(len(sprite))/2
+
+	nextDot := predictionDotInterval
+	for t := predictionStep; t <= horizon+predictionStep/2; t += predictionStep {
+		UpdateRocket(&ghost, predictionStep, groundLevel, hoverThrust)
+		x, y := ghost.Position()
+		if ghost.ImpactSpeed > 0 || ghost.TerrainCollision {
+			p.Impact = true
+			p.ImpactX, p.ImpactY = x+centerX, y+centerY
+			p.ImpactTime = t
+			p.ImpactSpeed = ghost.ImpactSpeed
+			p.ImpactOnSide = ghost.TerrainCollision
+			p.ImpactSlope = ghost.GroundSlope
+			p.ImpactHull = ghost.Hull
+			break
+		}
+		if t >= nextDot {
+			p.Points = append(p.Points, TrajectoryPoint{X: x + centerX, Y: y + centerY, T: t})
+			nextDot += predictionDotInterval
+		}
+		if math.IsNaN(x) || math.IsNaN(y) {
+			break
+		}
+	}
+	return p
+}
@@ -0,0 +1,24 @@
+package physics
+
+import "github.com/shameoff/rocket-in-console/pkg/objects"
+
+// Коэффициент увлечения ракеты ветром у поверхности (доля разницы скоростей за секунду)
+const windDrag = 0.3
+
+// ApplyWeather увлекает ракету ветром и раскачивает её турбулентностью в облаках.
+// Влияние ветра ослабевает с плотностью воздуха; без атмосферы погоды нет,
+// а стоящую на земле ракету ветер не сдвигает.
+func ApplyWeather(r *objects.Rocket, w *objects.Weather, dt float64, groundLevel int) {
+	if !CurrentBody.HasAtmosphere() || TerrainPenetration(r.X, r.Y+1, r.GetRocketSprite(), &objects.Ground) > 0 {
+		return
+	}
+	altitude := float64(groundLevel - r.Y)
+	density := AirDensity(altitude)
+
+	wind := w.WindAt(altitude)
+	r.Vx += (wind - r.Vx) * windDrag * density * dt
+
+	ax, ay := w.TurbulenceAt(altitude)
+	r.Vx += ax * dt
+	r.Vy += ay * dt
+}
@@ -0,0 +1,60 @@
+package render
+
+import (
+	"math"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Параметры мигания предупреждений
+const (
+	alertBlinkRate   = 2.0 // миганий в секунду
+	cautionBlinkTime = 3.0 // сколько секунд мигает новое предостережение, прежде чем гореть ровно
+)
+
+// Alert - предупреждение на панели
+type Alert struct {
+	Text    string
+	Warning bool    // предупреждение (красное, мигает всё время), иначе предостережение (жёлтое)
+	Age     float64 // секунды с момента включения
+}
+
+// AlertPanel - панель предупреждений: по строке на каждое, самые важные сверху
+type AlertPanel struct {
+	Alerts func() []Alert
+}
+
+// Size возвращает размер панели
+func (p *AlertPanel) Size() (int, int) {
+	alerts := p.Alerts()
+	width := 0
+	for _, alert := range alerts {
+		width = max(width, len([]rune(alert.Text))+4)
+	}
+	return width, len(alerts)
+}
+
+// Draw рисует плашки предупреждений; мигающая плашка чередует залитый и контурный вид
+func (p *AlertPanel) Draw(screen tcell.Screen, x, y int) {
+	width, _ := p.Size()
+	for i, alert := range p.Alerts() {
+		color := tcell.ColorYellow
+		if alert.Warning {
+			color = tcell.ColorRed
+		}
+		style := tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(color).Bold(true)
+		blinking := alert.Warning || alert.Age < cautionBlinkTime
+		if blinking && math.Mod(alert.Age*alertBlinkRate, 1) >= 0.5 {
+			style = tcell.StyleDefault.Foreground(color).Background(tcell.ColorBlack).Bold(true)
+		}
+		DrawText(screen, x, y+i, padRight(centered(alert.Text, width), width), style)
+	}
+}
+
+// centered дополняет строку пробелами слева, чтобы она оказалась посередине поля шириной width
+func centered(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return padRight("", (width-n)/2) + s
+	}
+	return s
+}
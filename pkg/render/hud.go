@@ -0,0 +1,201 @@
+package render
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+)
+
+// Anchor - угол или край экрана, к которому прижимается виджет HUD
+type Anchor int
+
+const (
+	AnchorTopLeft Anchor = iota
+	AnchorTopCenter
+	AnchorTopRight
+	AnchorBottomLeft
+	AnchorBottomCenter
+	AnchorBottomRight
+)
+
+var anchorNames = map[string]Anchor{
+	"top-left":      AnchorTopLeft,
+	"top-center":    AnchorTopCenter,
+	"top-right":     AnchorTopRight,
+	"bottom-left":   AnchorBottomLeft,
+	"bottom-center": AnchorBottomCenter,
+	"bottom-right":  AnchorBottomRight,
+}
+
+// ParseAnchor возвращает якорь по имени вида "top-left"
+func ParseAnchor(name string) (Anchor, error) {
+	anchor, ok := anchorNames[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown HUD anchor %q", name)
+	}
+	return anchor, nil
+}
+
+func (a Anchor) bottom() bool {
+	return a >= AnchorBottomLeft
+}
+
+// Widget - элемент HUD. Размер может меняться от кадра к кадру; виджет нулевого размера не рисуется.
+type Widget interface {
+	Size() (width, height int)
+	Draw(screen tcell.Screen, x, y int)
+}
+
+// TextWidget - виджет из строк текста одного стиля
+type TextWidget struct {
+	Lines func() []string
+	Style tcell.Style
+}
+
+// Size возвращает ширину самой длинной строки и число строк
+func (t *TextWidget) Size() (int, int) {
+	lines := t.Lines()
+	width := 0
+	for _, line := range lines {
+		width = max(width, len([]rune(line)))
+	}
+	return width, len(lines)
+}
+
+// Draw рисует строки одну под другой
+func (t *TextWidget) Draw(screen tcell.Screen, x, y int) {
+	DrawTextLines(screen, x, y, t.Lines(), t.Style)
+}
+
+// hudSlot - виджет, зарегистрированный в HUD
+type hudSlot struct {
+	name   string
+	anchor Anchor
+	widget Widget
+}
+
+// HUD раскладывает виджеты по якорям так, чтобы они не перекрывались.
+// Виджеты у одного якоря складываются в стопку в порядке добавления; если виджет
+// задевает уже размещённый, он сдвигается дальше от края, а если не помещается - скрывается.
+// Поэтому виджеты, добавленные раньше, важнее.
+type HUD struct {
+	Margin int // отступ от краёв экрана
+
+	slots         []hudSlot
+	width, height int
+}
+
+// NewHUD создаёт пустой HUD для экрана размером width x height
+func NewHUD(width, height int) *HUD {
+	return &HUD{Margin: 1, width: width, height: height}
+}
+
+// Add добавляет виджет name к якорю anchor
+func (h *HUD) Add(name string, anchor Anchor, widget Widget) {
+	h.slots = append(h.slots, hudSlot{name: name, anchor: anchor, widget: widget})
+}
+
+// Resize запоминает новый размер экрана (по событию tcell.EventResize)
+func (h *HUD) Resize(width, height int) {
+	h.width, h.height = width, height
+}
+
+// Layout размещает виджеты и возвращает их области; скрытых виджетов в результате нет
+func (h *HUD) Layout() map[string]objects.Rect {
+	placed := make(map[string]objects.Rect, len(h.slots))
+	var taken []objects.Rect
+	offsets := make(map[Anchor]int) // высота стопки у каждого якоря
+
+	for _, slot := range h.slots {
+		w, ht := slot.widget.Size()
+		if w == 0 || ht == 0 || w > h.width-2*h.Margin {
+			continue
+		}
+		rect := objects.Rect{W: w, H: ht}
+		switch slot.anchor {
+		case AnchorTopLeft, AnchorBottomLeft:
+			rect.X = h.Margin
+		case AnchorTopCenter, AnchorBottomCenter:
+			rect.X = (h.width - w) / 2
+		default:
+			rect.X = h.width - h.Margin - w
+		}
+		if slot.anchor.bottom() {
+			rect.Y = h.height - h.Margin - offsets[slot.anchor] - ht
+		} else {
+			rect.Y = h.Margin + offsets[slot.anchor]
+		}
+
+		// Сдвигаем виджет от края, пока он задевает уже размещённые
+		for moved := true; moved; {
+			moved = false
+			for _, other := range taken {
+				if !rect.Intersects(other) {
+					continue
+				}
+				if slot.anchor.bottom() {
+					rect.Y = other.Y - ht
+				} else {
+					rect.Y = other.Y + other.H
+				}
+				moved = true
+			}
+		}
+		if rect.Y < 0 || rect.Y+ht > h.height {
+			continue
+		}
+
+		taken = append(taken, rect)
+		placed[slot.name] = rect
+		if slot.anchor.bottom() {
+			offsets[slot.anchor] = h.height - h.Margin - rect.Y
+		} else {
+			offsets[slot.anchor] = rect.Y + ht - h.Margin
+		}
+	}
+	return placed
+}
+
+// Draw раскладывает и рисует все виджеты
+func (h *HUD) Draw(screen tcell.Screen) {
+	placed := h.Layout()
+	for _, slot := range h.slots {
+		if rect, ok := placed[slot.name]; ok {
+			slot.widget.Draw(screen, rect.X, rect.Y)
+		}
+	}
+}
+
+// HUDWidgetConfig - виджет и его якорь в пользовательской раскладке
+type HUDWidgetConfig struct {
+	Name   string
+	Anchor Anchor
+}
+
+// ParseHUDConfig разбирает раскладку вида "stats@top-right,stage,wind@bottom-left".
+// Виджет без якоря получает якорь из defaults; имена вне defaults считаются ошибкой.
+func ParseHUDConfig(spec string, defaults map[string]Anchor) ([]HUDWidgetConfig, error) {
+	var config []HUDWidgetConfig
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, anchorName, hasAnchor := strings.Cut(item, "@")
+		anchor, ok := defaults[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown HUD widget %q", name)
+		}
+		if hasAnchor {
+			var err error
+			if anchor, err = ParseAnchor(anchorName); err != nil {
+				return nil, err
+			}
+		}
+		config = append(config, HUDWidgetConfig{Name: name, Anchor: anchor})
+	}
+	return config, nil
+}
@@ -0,0 +1,458 @@
+package render
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+)
+
+// Параметры приборов
+const (
+	gaugeWidth       = 12   // длина полос шкал в клетках
+	tapeRows         = 9    // строк в ленте высотомера и вариометра (нечётное - текущее значение посередине)
+	maxGForce        = 5.0  // предел шкалы перегрузки
+	gForceSmoothing  = 0.2  // секунды сглаживания перегрузки
+	radarRange       = 30.0 // высота над рельефом, ниже которой включается радиовысотомер (3 км)
+	layerLabelLength = 5    // сколько букв названия слоя атмосферы помещается на ленте
+	fullBurnTime     = 1800 // секунды работы на полной тяге, при которых полоса ступени заполнена
+	horizonRadius    = 3    // радиус авиагоризонта в строках; по горизонтали он вдвое шире
+	minPathSpeed     = 0.1  // скорость, ниже которой направление полёта не показывается
+)
+
+// tapeSteps - допустимые цены деления лент
+var tapeSteps = []float64{0.1, 0.2, 0.5, 1, 2, 5, 10, 20, 50, 100, 200, 500, 1000}
+
+// barBlocks - символы для дробной части полосы, от 1/8 до полной клетки
+var barBlocks = []rune("▏▎▍▌▋▊▉█")
+
+// Instruments - приборная панель ракеты. Показания датчиков обновляются в Update,
+// а виджеты HUD, созданные методами панели, только рисуют их.
+type Instruments struct {
+	FullTank    float64 // запас топлива при полном баке
+	SafeDescent float64 // скорость снижения, выше которой посадка опасна
+
+	rocket      *objects.Rocket
+	groundLevel int
+	gForce      float64
+	prevVx      float64
+	prevVy      float64
+	primed      bool // предыдущая скорость известна и перегрузку можно считать
+}
+
+// NewInstruments создаёт панель приборов
+func NewInstruments(fullTank, safeDescent float64) *Instruments {
+	return &Instruments{FullTank: fullTank, SafeDescent: safeDescent}
+}
+
+// Reset переключает приборы на новую ракету, сбрасывая накопленные показания
+func (in *Instruments) Reset(r *objects.Rocket, groundLevel int) {
+	in.rocket = r
+	in.groundLevel = groundLevel
+	in.gForce = 1
+	in.primed = false
+}
+
+// Update снимает показания датчиков за шаг dt
+func (in *Instruments) Update(r *objects.Rocket, groundLevel int, dt float64) {
+	if r != in.rocket {
+		in.Reset(r, groundLevel)
+	}
+	if in.primed && dt > 0 {
+		// Перегрузка - ускорение без учёта гравитации: на площадке она равна 1g, в свободном падении - 0
+		gravity := physics.CalculateGravity(in.altitude())
+		ax := (r.Vx - in.prevVx) / dt
+		ay := gravity - (r.Vy-in.prevVy)/dt
+		g := math.Hypot(ax, ay) / physics.StandardGravity
+		in.gForce += (g - in.gForce) * min(1, dt/gForceSmoothing)
+	}
+	in.prevVx, in.prevVy = r.Vx, r.Vy
+	in.primed = true
+}
+
+// altitude возвращает высоту ракеты над уровнем старта в игровых единицах
+func (in *Instruments) altitude() float64 {
+	_, y := in.rocket.Position()
+	return float64(in.groundLevel) - y
+}
+
+// Fuel возвращает указатель топлива с полосой для каждой ступени
+func (in *Instruments) Fuel() Widget { return &fuelGauge{in} }
+
+// Throttle возвращает указатель вертикальной и горизонтальной тяги
+func (in *Instruments) Throttle() Widget { return &throttleGauge{in} }
+
+// VerticalSpeed возвращает вариометр - шкалу вертикальной скорости со стрелкой
+func (in *Instruments) VerticalSpeed() Widget { return &verticalSpeedTape{in} }
+
+// Altimeter возвращает ленту высотомера с отметками слоёв атмосферы
+func (in *Instruments) Altimeter() Widget { return &altimeterTape{in} }
+
+// GForce возвращает указатель перегрузки
+func (in *Instruments) GForce() Widget { return &gForceMeter{in} }
+
+// Radar возвращает радиовысотомер, который виден только у самой поверхности
+func (in *Instruments) Radar() Widget { return &radarAltimeter{in} }
+
+// Horizon возвращает авиагоризонт с указателем направления полёта
+func (in *Instruments) Horizon() Widget { return &horizonIndicator{in} }
+
+// fuelGauge показывает остаток общего бака. Бак у ступеней общий, но расходуют они его по-разному,
+// поэтому для каждой ступени полоса показывает, на сколько хватит остатка при её полной тяге.
+type fuelGauge struct{ in *Instruments }
+
+func (g *fuelGauge) Size() (int, int) {
+	if g.in.rocket == nil {
+		return 0, 0
+	}
+	nameWidth := 0
+	for _, stage := range objects.RocketStages {
+		nameWidth = max(nameWidth, len([]rune(stage.Name)))
+	}
+	return 1 + nameWidth + 1 + gaugeWidth + 6, 1 + len(objects.RocketStages)
+}
+
+func (g *fuelGauge) Draw(screen tcell.Screen, x, y int) {
+	r := g.in.rocket
+	width, _ := g.Size()
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+	fraction := r.Fuel / g.in.FullTank
+	DrawText(screen, x, y, padRight(fmt.Sprintf("FUEL %5.1f%%", fraction*100), width), style)
+
+	gravity := physics.CalculateGravity(g.in.altitude())
+	nameWidth := width - gaugeWidth - 8
+	for i, stage := range objects.RocketStages {
+		marker, rowStyle := " ", style.Foreground(tcell.ColorGray)
+		if i == r.ActiveStage {
+			marker, rowStyle = "►", style.Foreground(levelColor(fraction, 0.25, 0.1))
+		}
+		seconds := burnTime(r.Fuel, i, gravity)
+		line := marker + padRight(stage.Name, nameWidth) + " " +
+			Bar(seconds/fullBurnTime, gaugeWidth) + " " + formatSeconds(seconds)
+		DrawText(screen, x, y+1+i, padRight(line, width), rowStyle)
+	}
+}
+
+// burnTime возвращает, на сколько секунд хватит топлива fuel при полной тяге ступени stage
+func burnTime(fuel float64, stage int, gravity float64) float64 {
+	s := objects.RocketStages[stage]
+	rate := (s.MaxThrustY - gravity) * s.FuelConsumptionRate
+	if rate <= 0 {
+		return math.Inf(1)
+	}
+	return fuel / rate
+}
+
+// formatSeconds форматирует время в поле шириной 5 символов
+func formatSeconds(s float64) string {
+	if s > 9999 {
+		return "  ∞ s"
+	}
+	return fmt.Sprintf("%4.0fs", s)
+}
+
+// throttleGauge показывает долю вертикальной тяги от максимума ступени и направление боковой тяги
+type throttleGauge struct{ in *Instruments }
+
+func (g *throttleGauge) Size() (int, int) {
+	if g.in.rocket == nil {
+		return 0, 0
+	}
+	return 4 + gaugeWidth + 5, 2
+}
+
+func (g *throttleGauge) Draw(screen tcell.Screen, x, y int) {
+	r := g.in.rocket
+	stage := objects.RocketStages[r.ActiveStage]
+	style := tcell.StyleDefault.Foreground(tcell.ColorOrange).Background(tcell.ColorBlack)
+	throttle := r.ThrustY / stage.MaxThrustY
+	DrawText(screen, x, y, fmt.Sprintf("THR %s %3.0f%%", Bar(throttle, gaugeWidth), throttle*100), style)
+
+	// Боковая тяга: риска в центре шкалы - ноль, маркер смещается в сторону тяги
+	lateral := []rune(strings.Repeat("─", gaugeWidth+1))
+	center := gaugeWidth / 2
+	lateral[center] = '┼'
+	if stage.MaxThrustX > 0 {
+		offset := int(math.Round(r.ThrustX / stage.MaxThrustX * float64(center)))
+		lateral[center+max(-center, min(center, offset))] = '●'
+	}
+	DrawText(screen, x, y+1, "LAT "+string(lateral)+"    ", style)
+}
+
+// verticalSpeedTape - вариометр: неподвижная шкала с нулём посередине и стрелка текущей скорости.
+// Цена деления подбирается так, чтобы стрелка не уходила за шкалу.
+type verticalSpeedTape struct{ in *Instruments }
+
+func (t *verticalSpeedTape) Size() (int, int) {
+	if t.in.rocket == nil {
+		return 0, 0
+	}
+	return 9, tapeRows + 1
+}
+
+func (t *verticalSpeedTape) Draw(screen tcell.Screen, x, y int) {
+	r := t.in.rocket
+	climb := -r.Vy // вверх - положительная скорость
+	half := tapeRows / 2
+	step := tapeStep(math.Abs(climb) / float64(half))
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+	DrawText(screen, x, y, fmt.Sprintf("VS%+7.1f", climb), style)
+
+	pointer := half - int(math.Round(climb/step))
+	pointerStyle := style.Foreground(tcell.ColorGreen)
+	if -climb > t.in.SafeDescent {
+		pointerStyle = style.Foreground(tcell.ColorRed)
+	} else if climb < 0 {
+		pointerStyle = style.Foreground(tcell.ColorYellow)
+	}
+	for row := 0; row < tapeRows; row++ {
+		value := float64(half-row) * step
+		label := "      "
+		if (half-row)%2 == 0 {
+			label = fmt.Sprintf("%6s", formatTapeValue(value, step))
+		}
+		line, lineStyle := label+" ┤ ", style.Foreground(tcell.ColorGray)
+		if row == pointer {
+			line, lineStyle = label+" ◄─", pointerStyle
+		}
+		DrawText(screen, x, y+1+row, line, lineStyle)
+	}
+}
+
+// altimeterTape - лента высотомера, которая движется под неподвижным указателем.
+// Границы слоёв атмосферы и линия Кармана подписаны прямо на ленте.
+type altimeterTape struct{ in *Instruments }
+
+func (t *altimeterTape) Size() (int, int) {
+	if t.in.rocket == nil {
+		return 0, 0
+	}
+	return 8 + 1 + layerLabelLength, tapeRows + 1
+}
+
+func (t *altimeterTape) Draw(screen tcell.Screen, x, y int) {
+	altitudeKm := t.in.altitude() * physics.GameToRealScale / 1000
+	width, _ := t.Size()
+	half := tapeRows / 2
+	// Лента охватывает примерно четверть текущей высоты, но не меньше километра
+	step := tapeStep(max(1, altitudeKm/4) / float64(half))
+	base := math.Round(altitudeKm/step) * step
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+	DrawText(screen, x, y, padRight(fmt.Sprintf("ALT%7.2fkm", altitudeKm), width), style)
+
+	for row := 0; row < tapeRows; row++ {
+		value := base + float64(half-row)*step
+		label := "      "
+		if int(math.Round(value/step))%2 == 0 {
+			label = fmt.Sprintf("%6s", formatTapeValue(value, step))
+		}
+		line, lineStyle := label+" ┤ ", style.Foreground(tcell.ColorGray)
+		if row == half {
+			line, lineStyle = label+" ◄─", style.Foreground(tcell.ColorAqua)
+		}
+		if mark := layerMark(value-step/2, value+step/2); mark != "" {
+			line += mark
+		}
+		DrawText(screen, x, y+1+row, padRight(line, width), lineStyle)
+	}
+}
+
+// layerMark возвращает сокращённое название слоя атмосферы, граница которого попадает в [lo, hi) км
+func layerMark(lo, hi float64) string {
+	body := physics.CurrentBody
+	if !body.HasAtmosphere() {
+		return ""
+	}
+	if karman := body.KarmanLine / 1000; karman >= lo && karman < hi {
+		return "space"
+	}
+	for _, layer := range body.Layers {
+		if layer.BottomKm > 0 && layer.BottomKm >= lo && layer.BottomKm < hi {
+			return truncate(layer.Name, layerLabelLength)
+		}
+	}
+	return ""
+}
+
+// gForceMeter показывает сглаженную перегрузку, которую испытывает экипаж
+type gForceMeter struct{ in *Instruments }
+
+func (m *gForceMeter) Size() (int, int) {
+	if m.in.rocket == nil {
+		return 0, 0
+	}
+	return 2 + gaugeWidth + 6, 1
+}
+
+func (m *gForceMeter) Draw(screen tcell.Screen, x, y int) {
+	g := m.in.gForce
+	color := tcell.ColorGreen
+	switch {
+	case g > 0.8*maxGForce:
+		color = tcell.ColorRed
+	case g > 0.5*maxGForce:
+		color = tcell.ColorYellow
+	}
+	style := tcell.StyleDefault.Foreground(color).Background(tcell.ColorBlack)
+	DrawText(screen, x, y, fmt.Sprintf("G %s %4.1fg", Bar(g/maxGForce, gaugeWidth), g), style)
+}
+
+// radarAltimeter показывает высоту над рельефом под ракетой, когда до поверхности меньше radarRange
+type radarAltimeter struct{ in *Instruments }
+
+func (a *radarAltimeter) height() (float64, bool) {
+	if a.in.rocket == nil {
+		return 0, false
+	}
+	h := physics.HeightAboveTerrain(a.in.rocket)
+	return h, h < radarRange
+}
+
+func (a *radarAltimeter) Size() (int, int) {
+	if _, ok := a.height(); !ok {
+		return 0, 0
+	}
+	return 6 + gaugeWidth + 7, 1
+}
+
+func (a *radarAltimeter) Draw(screen tcell.Screen, x, y int) {
+	h, _ := a.height()
+	h = max(h, 0)
+	color := tcell.ColorGreen
+	if a.in.rocket.Vy > a.in.SafeDescent {
+		color = tcell.ColorRed
+	}
+	style := tcell.StyleDefault.Foreground(color).Background(tcell.ColorBlack)
+	meters := h * physics.GameToRealScale
+	DrawText(screen, x, y, fmt.Sprintf("RADAR %s %5.0fm", Bar(h/radarRange, gaugeWidth), meters), style)
+}
+
+// horizonIndicator - авиагоризонт. Ракета не наклоняется, поэтому линия горизонта неподвижна,
+// а маркер показывает, куда ракета летит: над горизонтом - набор высоты, под ним - снижение,
+// в стороне от центра - снос. Угол направления от вертикали равен atan2(Vx, -Vy).
+type horizonIndicator struct{ in *Instruments }
+
+func (h *horizonIndicator) Size() (int, int) {
+	if h.in.rocket == nil {
+		return 0, 0
+	}
+	return 4*horizonRadius + 3, 2*horizonRadius + 2
+}
+
+func (h *horizonIndicator) Draw(screen tcell.Screen, x, y int) {
+	r := h.in.rocket
+	width, _ := h.Size()
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+	speed := math.Hypot(r.Vx, r.Vy)
+	header := "FPA   --"
+	if speed >= minPathSpeed {
+		// Угол траектории над горизонтом: +90 - вертикально вверх, -90 - вниз
+		// Прибавление нуля убирает минус у округлённого до нуля угла
+		fpa := math.Round(math.Atan2(-r.Vy, math.Abs(r.Vx))*180/math.Pi) + 0
+		header = fmt.Sprintf("FPA %+4.0f°", fpa)
+	}
+	DrawText(screen, x, y, padRight(header, width), style)
+
+	skyStyle := style.Background(tcell.ColorNavy)
+	groundStyle := style.Background(tcell.ColorSaddleBrown)
+	center := 2*horizonRadius + 1
+	for row := 0; row <= 2*horizonRadius; row++ {
+		for col := 0; col < width; col++ {
+			ch, cellStyle := ' ', skyStyle
+			switch {
+			case row > horizonRadius:
+				cellStyle = groundStyle
+			case row == horizonRadius && col == center:
+				ch = '┼' // ось ракеты
+			case row == horizonRadius:
+				ch = '─'
+			}
+			screen.SetContent(x+col, y+1+row, ch, nil, cellStyle)
+		}
+	}
+	if speed < minPathSpeed {
+		return
+	}
+
+	angle := math.Atan2(r.Vx, -r.Vy)
+	row := horizonRadius - int(math.Round(math.Cos(angle)*horizonRadius))
+	col := center + int(math.Round(math.Sin(angle)*2*horizonRadius))
+	markerStyle := skyStyle.Foreground(tcell.ColorYellow)
+	if row > horizonRadius {
+		markerStyle = groundStyle.Foreground(tcell.ColorYellow)
+	}
+	if r.Vy > h.in.SafeDescent {
+		markerStyle = markerStyle.Foreground(tcell.ColorRed)
+	}
+	screen.SetContent(x+col, y+1+row, '◆', nil, markerStyle.Bold(true))
+}
+
+// Bar строит горизонтальную полосу шириной width, заполненную на долю fraction
+func Bar(fraction float64, width int) string {
+	if math.IsNaN(fraction) {
+		fraction = 0
+	}
+	fraction = max(0, min(1, fraction))
+	eighths := int(math.Round(fraction * float64(width*8)))
+	bar := make([]rune, width)
+	for i := range bar {
+		switch filled := eighths - i*8; {
+		case filled >= 8:
+			bar[i] = '█'
+		case filled > 0:
+			bar[i] = barBlocks[filled-1]
+		default:
+			bar[i] = '░'
+		}
+	}
+	return string(bar)
+}
+
+// tapeStep возвращает наименьшую цену деления ленты, не меньшую minStep
+func tapeStep(minStep float64) float64 {
+	for _, step := range tapeSteps {
+		if step >= minStep {
+			return step
+		}
+	}
+	return tapeSteps[len(tapeSteps)-1]
+}
+
+// formatTapeValue форматирует подпись деления с точностью, достаточной для цены деления step
+func formatTapeValue(value, step float64) string {
+	if step < 1 {
+		return fmt.Sprintf("%.1f", value)
+	}
+	return fmt.Sprintf("%.0f", value)
+}
+
+// levelColor выбирает цвет указателя запаса: жёлтый ниже caution, красный ниже warning
+func levelColor(fraction, caution, warning float64) tcell.Color {
+	switch {
+	case fraction < warning:
+		return tcell.ColorRed
+	case fraction < caution:
+		return tcell.ColorYellow
+	}
+	return tcell.ColorGreen
+}
+
+// padRight дополняет строку пробелами до ширины width, чтобы прибор затирал фон под собой
+func padRight(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
+
+// truncate обрезает строку до length символов
+func truncate(s string, length int) string {
+	if runes := []rune(s); len(runes) > length {
+		return string(runes[:length])
+	}
+	return s
+}
@@ -0,0 +1,223 @@
+package render
+
+import (
+	"sort"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// Priority - важность уведомления: более важные вытесняют менее важные и рисуются выше
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+	PriorityCritical
+)
+
+// Параметры анимации уведомлений
+const (
+	notificationFadeIn  = 0.3 // секунды появления
+	notificationFadeOut = 0.6 // секунды исчезновения
+	notificationHeight  = 3   // строк на одно уведомление в рамке
+)
+
+// Notification - сообщение в рамке поверх игры
+type Notification struct {
+	Key      string // уведомление с тем же ключом заменяет предыдущее (пустой - не заменяет)
+	Text     string
+	Priority Priority
+	Duration float64 // сколько секунд показывать (0 - пока не снимут через Dismiss)
+
+	age       float64
+	dismissed bool    // уведомление снято и доигрывает анимацию исчезновения
+	fadeAge   float64 // время с момента снятия
+	seq       int     // порядок поступления для уведомлений одного приоритета
+}
+
+// Notifier - очередь уведомлений HUD. Одновременно видно не больше MaxVisible уведомлений,
+// остальные ждут в очереди по приоритету; более важное уведомление вытесняет менее важное.
+type Notifier struct {
+	MaxVisible int
+
+	active  []*Notification
+	pending []*Notification
+	seq     int
+}
+
+// NewNotifier создаёт очередь, показывающую до maxVisible уведомлений сразу
+func NewNotifier(maxVisible int) *Notifier {
+	return &Notifier{MaxVisible: maxVisible}
+}
+
+// Push добавляет уведомление в очередь
+func (n *Notifier) Push(note Notification) {
+	if note.Key != "" {
+		if existing := n.find(note.Key); existing != nil {
+			// Обновляем текст и продлеваем показ, не перезапуская анимацию появления
+			existing.Text = note.Text
+			existing.Priority = note.Priority
+			existing.Duration = note.Duration
+			existing.age = min(existing.age, notificationFadeIn)
+			existing.dismissed = false
+			existing.fadeAge = 0
+			return
+		}
+	}
+	n.seq++
+	note.seq = n.seq
+	n.pending = append(n.pending, &note)
+	n.promote()
+}
+
+// Dismiss снимает уведомление с ключом key; видимое уведомление плавно исчезает
+func (n *Notifier) Dismiss(key string) {
+	for i, note := range n.pending {
+		if note.Key == key {
+			n.pending = append(n.pending[:i], n.pending[i+1:]...)
+			return
+		}
+	}
+	if note := n.find(key); note != nil {
+		note.dismissed = true
+	}
+}
+
+// Clear убирает все уведомления
+func (n *Notifier) Clear() {
+	n.active = nil
+	n.pending = nil
+}
+
+// Update продвигает анимации и сроки показа видимых уведомлений
+func (n *Notifier) Update(dt float64) {
+	alive := n.active[:0]
+	for _, note := range n.active {
+		note.age += dt
+		if note.Duration > 0 && note.age >= note.Duration {
+			note.dismissed = true
+		}
+		if note.dismissed {
+			note.fadeAge += dt
+			if note.fadeAge >= notificationFadeOut {
+				continue
+			}
+		}
+		alive = append(alive, note)
+	}
+	n.active = alive
+	n.promote()
+}
+
+// Size возвращает размер стопки уведомлений
+func (n *Notifier) Size() (int, int) {
+	width := 0
+	for _, note := range n.active {
+		width = max(width, len([]rune(note.Text))+4)
+	}
+	return width, len(n.active) * notificationHeight
+}
+
+// Draw рисует стопку уведомлений, выравнивая рамки по центру стопки
+func (n *Notifier) Draw(screen tcell.Screen, x, y int) {
+	width, _ := n.Size()
+	for i, note := range n.active {
+		boxWidth := len([]rune(note.Text)) + 4
+		drawBox(screen, x+(width-boxWidth)/2, y+i*notificationHeight, note.Text, note.style())
+	}
+}
+
+// find ищет видимое или ожидающее уведомление по ключу
+func (n *Notifier) find(key string) *Notification {
+	for _, note := range n.active {
+		if note.Key == key {
+			return note
+		}
+	}
+	for _, note := range n.pending {
+		if note.Key == key {
+			return note
+		}
+	}
+	return nil
+}
+
+// promote переносит уведомления из очереди на экран по приоритету,
+// вытесняя обратно в очередь видимые уведомления с меньшим приоритетом
+func (n *Notifier) promote() {
+	sort.SliceStable(n.pending, func(i, j int) bool {
+		return n.pending[i].before(n.pending[j])
+	})
+	for len(n.pending) > 0 {
+		next := n.pending[0]
+		if len(n.active) >= n.MaxVisible {
+			weakest := n.weakestActive()
+			if weakest < 0 || n.active[weakest].Priority >= next.Priority {
+				break
+			}
+			// Вытесненное уведомление вернётся, когда освободится место
+			evicted := n.active[weakest]
+			n.active = append(n.active[:weakest], n.active[weakest+1:]...)
+			n.pending = append(n.pending, evicted)
+		}
+		n.pending = n.pending[1:]
+		n.active = append(n.active, next)
+		sort.SliceStable(n.pending, func(i, j int) bool {
+			return n.pending[i].before(n.pending[j])
+		})
+	}
+	// Более важные уведомления рисуются выше
+	sort.SliceStable(n.active, func(i, j int) bool {
+		return n.active[i].before(n.active[j])
+	})
+}
+
+// weakestActive возвращает индекс видимого уведомления с наименьшим приоритетом
+// (среди равных - самого свежего); снятые уведомления не вытесняются
+func (n *Notifier) weakestActive() int {
+	weakest := -1
+	for i, note := range n.active {
+		if note.dismissed {
+			continue
+		}
+		if weakest < 0 || n.active[weakest].before(note) {
+			weakest = i
+		}
+	}
+	return weakest
+}
+
+// before определяет порядок уведомлений: сначала по приоритету, затем по времени поступления
+func (note *Notification) before(other *Notification) bool {
+	if note.Priority != other.Priority {
+		return note.Priority > other.Priority
+	}
+	return note.seq < other.seq
+}
+
+// style возвращает стиль рамки с учётом приоритета и анимации появления и исчезновения
+func (note *Notification) style() tcell.Style {
+	var r, g, b int32
+	switch note.Priority {
+	case PriorityLow:
+		r, g, b = 160, 160, 160
+	case PriorityNormal:
+		r, g, b = 0, 200, 255
+	case PriorityHigh:
+		r, g, b = 200, 0, 200
+	default:
+		r, g, b = 255, 60, 60
+	}
+	alpha := 1.0
+	if note.age < notificationFadeIn {
+		alpha = note.age / notificationFadeIn
+	}
+	if note.dismissed {
+		alpha = min(alpha, 1-note.fadeAge/notificationFadeOut)
+	}
+	// Терминал не умеет прозрачность, поэтому уведомление "тает", темнея к фону
+	alpha = max(alpha, 0.2)
+	fg := tcell.NewRGBColor(int32(float64(r)*alpha), int32(float64(g)*alpha), int32(float64(b)*alpha))
+	return tcell.StyleDefault.Foreground(fg).Background(tcell.ColorBlack)
+}
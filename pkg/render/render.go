@@ -19,12 +19,29 @@ func DrawSprite(screen tcell.Screen, x, y int, sprite []string, fg, bg tcell.Col
 	}
 }
 
+// DrawGhost рисует спрайт полупрозрачным: тусклым цветом поверх фона, не закрашивая его
+func DrawGhost(screen tcell.Screen, x, y int, sprite []string, fg tcell.Color) {
+	for dy, line := range sprite {
+		for dx, ch := range []rune(line) {
+			if ch == ' ' {
+				continue
+			}
+			_, _, oldStyle, _ := screen.GetContent(x+dx, y+dy)
+			_, bg, _ := oldStyle.Decompose()
+			screen.SetContent(x+dx, y+dy, ch, nil, tcell.StyleDefault.Foreground(fg).Background(bg).Dim(true))
+		}
+	}
+}
+
 // DrawText отображает строку текста на экране в указанной позиции с указанным стилем
 func DrawText(screen tcell.Screen, x, y int, text string, style tcell.Style) {
-	// Проходим по каждой руне (символу) в строке
-	for i, r := range text {
+	// Проходим по каждой руне (символу) в строке; индекс range - это смещение в байтах,
+	// поэтому колонку считаем отдельно, чтобы не было разрывов на многобайтовых символах
+	col := 0
+	for _, r := range text {
 		// Устанавливаем содержимое ячейки экрана в указанной позиции
-		screen.SetContent(x+i, y, r, nil, style)
+		screen.SetContent(x+col, y, r, nil, style)
+		col++
 	}
 }
 
@@ -37,10 +54,8 @@ func DrawTextLines(screen tcell.Screen, x, y int, lines []string, style tcell.St
 	}
 }
 
-// DrawStats отображает статистические данные о полете на экране
-func DrawStats(screen tcell.Screen, rocket *objects.Rocket, groundLevel int) {
-	style := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
-
+// StatsLines возвращает строки статистики полёта для виджета HUD
+func StatsLines(rocket *objects.Rocket, groundLevel int) []string {
 	// Расчет скорости (сохраняем знак для определения направления)
 	speedY := math.Abs(rocket.Vy)
 	speedX := rocket.Vx // Сохраняем знак для горизонтальной скорости
@@ -81,24 +96,27 @@ func DrawStats(screen tcell.Screen, rocket *objects.Rocket, groundLevel int) {
 		fmt.Sprintf("Thrust: V=%.2f H=%.2f", rocket.ThrustY, rocket.ThrustX),
 		fmt.Sprintf("Gravity: %.2f", currentGravity),
 		fmt.Sprintf("Fuel: %.1f%% (Rate: %.1fx)", rocket.Fuel, fuelConsumptionRate),
+		fmt.Sprintf("Hull: %.0f%%", rocket.Hull/objects.MaxHull*100),
+		fmt.Sprintf("Engine: %.0f%%", rocket.ActiveEngineHealth()/objects.MaxEngineHealth*100),
+		fmt.Sprintf("Temp: %.0f", rocket.Temperature),
+	}
+
+	// Предупреждаем о перегреве
+	if rocket.Temperature > objects.OverheatTemperature {
+		stats = append(stats, "!! OVERHEAT !!")
 	}
 
 	// Если мы находимся в космосе (выше линии Кармана), добавляем индикатор
-	if altitudeKm > physics.KarmanLine/1000.0 {
+	if physics.CurrentBody.InSpace(altitude) {
 		stats = append(stats, "*** SPACE ***")
 	}
 
-	// Отображаем статистику в углу экрана
-	width, _ := screen.Size()
-	x := width - 25 // отступ от правого края
-	y := 1          // начинаем немного ниже верха экрана
-
-	// Используем новую функцию для отрисовки всех строк статистики
-	DrawTextLines(screen, x, y, stats, style)
+	return stats
 }
 
-// GetSkyColor returns realistic atmospheric layer colors based on altitude
-func GetSkyColor(r *objects.Rocket, groundLevel int) tcell.Color {
+// GetSkyColor returns realistic atmospheric layer colors based on altitude,
+// darkened at night and tinted at sunrise and sunset
+func GetSkyColor(r *objects.Rocket, groundLevel int, clock *objects.DayClock) tcell.Color {
 	// Calculate altitude in arbitrary units
 	altitude := float64(groundLevel - r.Y)
 
@@ -106,30 +124,35 @@ func GetSkyColor(r *objects.Rocket, groundLevel int) tcell.Color {
 	// Let's say each unit is about 100 meters
 	altitudeKm := altitude / 10.0
 
-	switch {
-	case altitudeKm < 12: // Troposphere (0-12 km)
-		// Gradual transition from light blue to darker blue
-		blueIntensity := uint8(255 - (altitude * 5))
-		if blueIntensity < 100 {
-			blueIntensity = 100
-		}
-		return tcell.NewRGBColor(100, 100, int32(blueIntensity))
-
-	case altitudeKm < 50: // Stratosphere (12-50 km)
-		// Gradual transition from deep blue to indigo
-		progress := (altitudeKm - 12) / 38
-		blue := uint8(100 - progress*50)
-		return tcell.NewRGBColor(0, 0, int32(blue+50))
+	body := physics.CurrentBody
+	sky := body.SkyColor(altitudeKm)
 
-	case altitudeKm < 85: // Mesosphere (50-85 km)
-		// Dark purple transitioning to near black
-		progress := (altitudeKm - 50) / 35
-		val := uint8(50 - progress*50)
-		return tcell.NewRGBColor(int32(val/2), 0, int32(val))
+	// Night sky keeps only a faint glow of the daytime color
+	daylight := clock.Daylight()
+	light := nightSkyLevel + (1-nightSkyLevel)*daylight
+	fr, fg, fb := float64(sky.R)*light, float64(sky.G)*light, float64(sky.B)*light
 
-	default: // Thermosphere (85+ km) / Space
-		return tcell.ColorBlack
+	// Sunrise and sunset tint the lower atmosphere orange
+	if body.HasAtmosphere() && altitudeKm < 12 {
+		tint := clock.TwilightTint() * 0.6
+		fr += (twilightRed - fr) * tint
+		fg += (twilightGreen - fg) * tint
+		fb += (twilightBlue - fb) * tint
 	}
+	return tcell.NewRGBColor(int32(fr), int32(fg), int32(fb))
+}
+
+// Night sky brightness relative to day and the twilight tint color
+const (
+	nightSkyLevel = 0.15
+	twilightRed   = 230.0
+	twilightGreen = 110.0
+	twilightBlue  = 60.0
+)
+
+// bodyColor converts a body palette color into a terminal color
+func bodyColor(c physics.RGB) tcell.Color {
+	return tcell.NewRGBColor(c.R, c.G, c.B)
 }
 
 func DrawTrees(screen tcell.Screen, trees []objects.Tree, cameraX, cameraY, screenWidth, screenHeight int) {
@@ -138,7 +161,7 @@ func DrawTrees(screen tcell.Screen, trees []objects.Tree, cameraX, cameraY, scre
 		screenY := tree.Y - cameraY
 		if screenX+len(tree.Sprite[0]) >= 0 && screenX < screenWidth &&
 			screenY+len(tree.Sprite) >= 0 && screenY < screenHeight {
-			DrawSprite(screen, screenX, screenY, tree.Sprite, tcell.ColorGreen, tcell.ColorBlack)
+			DrawSprite(screen, screenX, screenY, tree.Sprite, shade(tcell.ColorGreen), tcell.ColorBlack)
 		}
 	}
 }
@@ -149,174 +172,374 @@ func DrawClouds(screen tcell.Screen, clouds []objects.Cloud, cameraX, cameraY, s
 		screenY := cloud.Y - cameraY
 		if screenX+len(cloud.Sprite[0]) >= 0 && screenX < screenWidth &&
 			screenY+len(cloud.Sprite) >= 0 && screenY < screenHeight {
-			DrawSprite(screen, screenX, screenY, cloud.Sprite, tcell.ColorWhite, tcell.ColorBlack)
+			DrawSprite(screen, screenX, screenY, cloud.Sprite, shade(tcell.ColorWhite), tcell.ColorBlack)
 		}
 	}
 }
 
-func DrawStars(screen tcell.Screen, cameraX, cameraY, screenWidth, screenHeight int, stars []objects.Star, isStarAt func(x, y int) bool) {
+// DrawStars рисует звёзды на уже закрашенном небе, сохраняя его фон;
+// visibility (0..1) задаёт, какая доля звёзд видна - от самых ярких к тусклым
+func DrawStars(screen tcell.Screen, cameraX, cameraY, screenWidth, screenHeight int, isStarAt func(x, y int) bool, visibility float64) {
+	if visibility <= 0 {
+		return
+	}
 	for sy := 0; sy < screenHeight; sy++ {
 		for sx := 0; sx < screenWidth; sx++ {
 			worldX := sx + cameraX
 			worldY := sy + cameraY
-			if isStarAt(worldX, worldY) {
-				screen.SetContent(sx, sy, '*', nil, tcell.StyleDefault.Foreground(tcell.ColorYellow).Background(tcell.ColorBlack))
+			if isStarAt(worldX, worldY) && objects.StarBrightness(worldX, worldY) < visibility {
+				_, _, oldStyle, _ := screen.GetContent(sx, sy)
+				_, bg, _ := oldStyle.Decompose()
+				screen.SetContent(sx, sy, '*', nil, tcell.StyleDefault.Foreground(tcell.ColorYellow).Background(bg))
 			}
 		}
 	}
 }
 
-func DrawGround(screen tcell.Screen, cameraX, cameraY, screenWidth, screenHeight, groundLevel int) {
-	screenY := groundLevel - cameraY
-	if screenY >= 0 && screenY < screenHeight {
-		for x := 0; x < screenWidth; x++ {
-			screen.SetContent(x, screenY, '=', nil, tcell.StyleDefault.Foreground(tcell.ColorGreen).Background(tcell.ColorBlack))
+// DrawTerrain рисует рельеф: поверхность символами наклона, под ней - грунт.
+// Посадочные площадки выделяются отдельным цветом.
+func DrawTerrain(screen tcell.Screen, terrain *objects.Terrain, cameraX, cameraY, screenWidth, screenHeight int) {
+	body := physics.CurrentBody
+	surfaceStyle := tcell.StyleDefault.Foreground(shade(bodyColor(body.SurfaceColor))).Background(tcell.ColorBlack)
+	padStyle := tcell.StyleDefault.Foreground(tcell.ColorYellow).Background(tcell.ColorBlack)
+	soilStyle := tcell.StyleDefault.Foreground(shade(bodyColor(body.SoilColor))).Background(tcell.ColorBlack)
+
+	for sx := 0; sx < screenWidth; sx++ {
+		worldX := sx + cameraX
+		surface := terrain.SurfaceAt(worldX)
+		screenY := surface - cameraY
+		if screenY >= screenHeight {
+			continue
+		}
+
+		// Символ поверхности зависит от наклона между соседними колонками
+		ch, style := '_', surfaceStyle
+		left := terrain.SurfaceAt(worldX - 1)
+		right := terrain.SurfaceAt(worldX + 1)
+		switch {
+		case terrain.IsPad(worldX):
+			ch, style = '=', padStyle
+		case left > surface && right > surface:
+			ch = '^'
+		case left > right:
+			ch = '/'
+		case left < right:
+			ch = '\\'
+		}
+		if screenY >= 0 {
+			screen.SetContent(sx, screenY, ch, nil, style)
+		}
+
+		// Заполняем всё под поверхностью грунтом
+		for y := max(screenY+1, 0); y < screenHeight; y++ {
+			screen.SetContent(sx, y, ':', nil, soilStyle)
 		}
 	}
 }
 
+// DrawNotificationBox рисует одно уведомление в рамке в правом верхнем углу экрана
 func DrawNotificationBox(screen tcell.Screen, screenWidth int, message string) {
-	boxWidth := len(message) + 4
-	startX := screenWidth - boxWidth
-	startY := 0
-	style := tcell.StyleDefault.Foreground(tcell.ColorPurple).Background(tcell.ColorBlack)
-
-	// Верхняя граница
-	for x := startX; x < startX+boxWidth; x++ {
-		var ch rune
-		if x == startX || x == startX+boxWidth-1 {
-			ch = '+'
-		} else {
-			ch = '-'
+	boxWidth := len([]rune(message)) + 4
+	drawBox(screen, screenWidth-boxWidth, 0, message, tcell.StyleDefault.Foreground(tcell.ColorPurple).Background(tcell.ColorBlack))
+}
+
+// drawBox рисует текст в рамке высотой в три строки с левым верхним углом в (startX, startY)
+func drawBox(screen tcell.Screen, startX, startY int, message string, style tcell.Style) {
+	boxWidth := len([]rune(message)) + 4
+
+	// Верхняя и нижняя границы
+	for _, y := range []int{startY, startY + 2} {
+		for x := startX; x < startX+boxWidth; x++ {
+			ch := '-'
+			if x == startX || x == startX+boxWidth-1 {
+				ch = '+'
+			}
+			screen.SetContent(x, y, ch, nil, style)
 		}
-		screen.SetContent(x, startY, ch, nil, style)
 	}
 
 	// Средняя строка с текстом
 	midY := startY + 1
 	screen.SetContent(startX, midY, '|', nil, style)
-	for i := 0; i < boxWidth-2; i++ {
-		ch := ' '
-		if i == 1 && len(message) > 0 {
-			for j, r := range message {
-				screen.SetContent(startX+1+j, midY, r, nil, style)
-			}
-			i = len(message)
-		}
-		screen.SetContent(startX+1+i, midY, ch, nil, style)
-	}
+	screen.SetContent(startX+1, midY, ' ', nil, style)
+	DrawText(screen, startX+2, midY, message, style)
+	screen.SetContent(startX+boxWidth-2, midY, ' ', nil, style)
 	screen.SetContent(startX+boxWidth-1, midY, '|', nil, style)
+}
 
-	// Нижняя граница
-	bottomY := startY + 2
-	for x := startX; x < startX+boxWidth; x++ {
-		var ch rune
-		if x == startX || x == startX+boxWidth-1 {
-			ch = '+'
-		} else {
-			ch = '-'
+// DrawParticles рисует частицы выхлопа, дыма и обломков; вид зависит от типа и возраста частицы.
+func DrawParticles(screen tcell.Screen, particles []objects.Particle, cameraX, cameraY, screenWidth, screenHeight int) {
+	for i := range particles {
+		p := &particles[i]
+		screenX := int(math.Floor(p.X)) - cameraX
+		screenY := int(math.Floor(p.Y)) - cameraY
+		if screenX < 0 || screenX >= screenWidth || screenY < 0 || screenY >= screenHeight {
+			continue
 		}
-		screen.SetContent(x, bottomY, ch, nil, style)
+		ch, color := particleGlyph(p)
+		// Сохраняем фон ячейки, чтобы частицы не "вырезали" небо
+		_, _, oldStyle, _ := screen.GetContent(screenX, screenY)
+		_, bg, _ := oldStyle.Decompose()
+		screen.SetContent(screenX, screenY, ch, nil, tcell.StyleDefault.Foreground(color).Background(bg))
 	}
 }
 
-// DrawExhaust рисует след от сопел, если активирована тяга в горизонтальном или вертикальном направлении.
-func DrawExhaust(screen tcell.Screen, rocket *objects.Rocket, cameraX, cameraY int) {
-	threshold := 0.5
-	rocketSprite := rocket.GetRocketSprite()
-	width := len(rocketSprite[0])
-	height := len(rocketSprite)
-
-	// Рассчитаем текущую величину гравитации для определения порога тяги
-	altitude := float64(objects.GroundLevel - rocket.Y)
-	currentGravity := physics.CalculateGravity(altitude)
-
-	// Получаем текущую ступень
-	currentStage := objects.RocketStages[rocket.ActiveStage]
+// particleGlyph подбирает символ и цвет частицы по её типу и возрасту
+func particleGlyph(p *objects.Particle) (rune, tcell.Color) {
+	age := p.Age()
+	switch p.Kind {
+	case objects.ParticleFlame:
+		switch {
+		case age < 0.3:
+			return '*', tcell.ColorYellow
+		case age < 0.7:
+			return '+', tcell.ColorOrange
+		default:
+			return '.', tcell.ColorRed
+		}
+	case objects.ParticleThruster:
+		if age < 0.5 {
+			return '=', tcell.ColorBlue
+		}
+		return '-', tcell.ColorDarkBlue
+	case objects.ParticleSmoke:
+		switch {
+		case age < 0.3:
+			return 'O', tcell.ColorSilver
+		case age < 0.7:
+			return 'o', tcell.ColorGray
+		default:
+			return '.', tcell.ColorDarkGray
+		}
+	default: // objects.ParticleDebris
+		switch {
+		case age < 0.2:
+			return '#', tcell.ColorYellow
+		case age < 0.6:
+			return '*', tcell.ColorRed
+		default:
+			return ',', tcell.ColorGray
+		}
+	}
+}
 
-	// Горизонтальный след (вспомогательные двигатели):
-	blueStyle := tcell.StyleDefault.Foreground(tcell.ColorBlue).Background(tcell.ColorBlack)
-	redStyle := tcell.StyleDefault.Foreground(tcell.ColorRed).Background(tcell.ColorBlack)
+// sparkBlocks - символы для мини-графика высоты
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
 
-	if rocket.ThrustX > threshold {
-		// Количество символов для горизонтальных двигателей зависит от макс. тяги ступени
-		flameLength := int(currentStage.MaxThrustX) + 1
-		if flameLength > 5 {
-			flameLength = 5 // Ограничиваем максимальную длину пламени
+// Sparkline строит однострочный график значений заданной ширины
+func Sparkline(values []float64, width int) string {
+	if len(values) == 0 || width <= 0 {
+		return ""
+	}
+	// Сжимаем выборку до нужной ширины, беря максимум в каждом окне
+	if len(values) > width {
+		compressed := make([]float64, width)
+		for i := range compressed {
+			from := i * len(values) / width
+			to := (i + 1) * len(values) / width
+			m := values[from]
+			for _, v := range values[from:to] {
+				m = math.Max(m, v)
+			}
+			compressed[i] = m
 		}
-		
-		flame := ""
-		for i := 0; i < flameLength; i++ {
-			flame += "="
+		values = compressed
+	}
+
+	minV, maxV := values[0], values[0]
+	for _, v := range values {
+		minV = math.Min(minV, v)
+		maxV = math.Max(maxV, v)
+	}
+	line := make([]rune, len(values))
+	for i, v := range values {
+		level := 0
+		if maxV > minV {
+			level = int((v - minV) / (maxV - minV) * float64(len(sparkBlocks)-1))
 		}
-		flame += ">"
-		
-		x := rocket.X - cameraX - len(flame)
-		y1 := rocket.Y - cameraY + height/3
-		y2 := rocket.Y - cameraY + (2 * height / 3)
-		for i, r := range flame {
-			screen.SetContent(x+i, y1, r, nil, blueStyle)
-			screen.SetContent(x+i, y2, r, nil, blueStyle)
+		line[i] = sparkBlocks[level]
+	}
+	return string(line)
+}
+
+// formatEngineHealth форматирует состояние двигателей всех ступеней в одну строку
+func formatEngineHealth(health []float64) string {
+	parts := ""
+	for i, h := range health {
+		if i > 0 {
+			parts += " "
 		}
-	} else if rocket.ThrustX < -threshold {
-		// Количество символов для горизонтальных двигателей зависит от макс. тяги ступени
-		flameLength := int(currentStage.MaxThrustX) + 1
-		if flameLength > 5 {
-			flameLength = 5 // Ограничиваем максимальную длину пламени
+		parts += fmt.Sprintf("%.0f%%", h/objects.MaxEngineHealth*100)
+	}
+	return parts
+}
+
+// DrawCrashReport рисует по центру экрана окно с отчётом о крушении и вариантами продолжения
+func DrawCrashReport(screen tcell.Screen, report *objects.CrashReport) {
+	const boxWidth = 44
+	lines := []string{
+		"CRASH REPORT",
+		"",
+		fmt.Sprintf("Cause:        %s", report.Cause),
+		fmt.Sprintf("Impact speed: %.2f", report.ImpactSpeed),
+		fmt.Sprintf("Altitude:     %.2f km", report.Altitude/10.0),
+		fmt.Sprintf("Max altitude: %.2f km", report.MaxAltitude/10.0),
+		fmt.Sprintf("Flight time:  %.1f s", report.FlightTime),
+		fmt.Sprintf("Fuel left:    %.1f", report.FuelLeft),
+		fmt.Sprintf("Stage:        %s", report.Stage),
+		fmt.Sprintf("Hull:         %.0f%%", report.Hull/objects.MaxHull*100),
+		fmt.Sprintf("Engines:      %s", formatEngineHealth(report.EngineHealth)),
+		fmt.Sprintf("Temperature:  %.0f", report.Temperature),
+		"",
+		"Altitude history:",
+		Sparkline(report.AltitudeHistory, boxWidth-4),
+		"",
+		"[R] Respawn  [N] New world  [Q] Quit",
+	}
+
+	width, height := screen.Size()
+	boxHeight := len(lines) + 2
+	startX := (width - boxWidth) / 2
+	startY := (height - boxHeight) / 2
+	style := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+	borderStyle := style.Foreground(tcell.ColorRed)
+
+	for y := 0; y < boxHeight; y++ {
+		for x := 0; x < boxWidth; x++ {
+			ch := ' '
+			switch {
+			case (y == 0 || y == boxHeight-1) && (x == 0 || x == boxWidth-1):
+				ch = '+'
+			case y == 0 || y == boxHeight-1:
+				ch = '-'
+			case x == 0 || x == boxWidth-1:
+				ch = '|'
+			}
+			s := style
+			if ch != ' ' {
+				s = borderStyle
+			}
+			screen.SetContent(startX+x, startY+y, ch, nil, s)
 		}
-		
-		flame := "<"
-		for i := 0; i < flameLength; i++ {
-			flame += "="
+	}
+	DrawTextLines(screen, startX+2, startY+1, lines, style)
+}
+
+// DrawEntities - система отрисовки: рисует все сущности мира w, у которых есть позиция и спрайт
+func DrawEntities(screen tcell.Screen, w *objects.EntityWorld, cameraX, cameraY, screenWidth, screenHeight int) {
+	w.Sprites.Each(func(e objects.Entity, s *objects.Sprite) {
+		pos := w.Positions.Get(e)
+		if pos == nil {
+			return
 		}
-		
-		x := rocket.X - cameraX + width
-		y1 := rocket.Y - cameraY + height/3
-		y2 := rocket.Y - cameraY + (2 * height / 3)
-		for i, r := range flame {
-			screen.SetContent(x+i, y1, r, nil, blueStyle)
-			screen.SetContent(x+i, y2, r, nil, blueStyle)
+		sprite := w.SpriteOf(e)
+		x, y := pos.Cell()
+		screenX := x - cameraX
+		screenY := y - cameraY
+		if screenX+len(sprite[0]) >= 0 && screenX < screenWidth &&
+			screenY+len(sprite) >= 0 && screenY < screenHeight {
+			DrawSprite(screen, screenX, screenY, sprite, tcell.NewHexColor(s.Color), tcell.ColorBlack)
 		}
+	})
+}
+
+// WindText возвращает строку о скорости и направлении ветра на текущей высоте (пустую, если атмосферы нет)
+func WindText(weather *objects.Weather, altitude float64) string {
+	if !physics.CurrentBody.HasAtmosphere() {
+		return ""
 	}
+	wind := weather.WindAt(altitude)
+	direction := ""
+	if wind > 0.05 {
+		direction = "►"
+	} else if wind < -0.05 {
+		direction = "◄"
+	}
+	return fmt.Sprintf("Wind:%.1f %s (%s)", math.Abs(wind), direction, weather.Name)
+}
+
+// AmbientLight - освещённость сцены от 0 (ночь) до 1 (день); ночью декорации рисуются темнее
+var AmbientLight = 1.0
 
-	// Вертикальный след (главный двигатель)
-	// Визуализация на основе мощности ступени: чем больше MaxThrustY, тем больше огоньков
-	var flamePower string
-	
-	// Рассчитываем количество символов в зависимости от макс. тяги ступени
-	flameSymbols := int(currentStage.MaxThrustY / 5.0)
-	if flameSymbols < 1 {
-		flameSymbols = 1
-	} else if flameSymbols > 6 {
-		flameSymbols = 6 // Ограничиваем макс. количество
+// Минимальная яркость декораций в самую тёмную ночь
+const minAmbientLight = 0.35
+
+// shade затемняет цвет декорации в соответствии с AmbientLight
+func shade(color tcell.Color) tcell.Color {
+	if AmbientLight >= 1 {
+		return color
 	}
+	level := minAmbientLight + (1-minAmbientLight)*math.Max(AmbientLight, 0)
+	r, g, b := color.RGB()
+	return tcell.NewRGBColor(int32(float64(r)*level), int32(float64(g)*level), int32(float64(b)*level))
+}
 
-	// Формируем строку с огоньками
-	for i := 0; i < flameSymbols; i++ {
-		flamePower += "v"
+// StarVisibility возвращает долю видимых звёзд: ночью, выше атмосферы и на телах без неё видны все,
+// днём у поверхности звёзд не видно
+func StarVisibility(altitude float64, clock *objects.DayClock) float64 {
+	if !physics.CurrentBody.HasAtmosphere() {
+		return 1
 	}
+	altitudeKm := altitude / 10.0
+	spaceKm := physics.CurrentBody.KarmanLine / 1000
+	space := math.Max(0, math.Min(1, (altitudeKm-12)/(spaceKm-12)))
+	return math.Max(1-clock.Daylight(), space)
+}
 
-	if rocket.ThrustY > currentGravity+threshold {
-		x1 := rocket.X - cameraX + width/3
-		x2 := rocket.X - cameraX + (2*width)/3 - len(flamePower)
-		y := rocket.Y - cameraY + height
-		for i, r := range flamePower {
-			screen.SetContent(x1+i, y, r, nil, redStyle)
-			screen.SetContent(x2+i, y, r, nil, redStyle)
-		}
-	} else if rocket.ThrustY < currentGravity-threshold {
-		// Преобразуем символы в '^' для обратной тяги
-		upFlamePower := ""
-		for i := 0; i < flameSymbols; i++ {
-			upFlamePower += "^"
+// DrawSunAndMoon рисует солнце днём и луну ночью, двигая их по дуге через небо
+func DrawSunAndMoon(screen tcell.Screen, clock *objects.DayClock, screenWidth, screenHeight int) {
+	if progress, visible := clock.SunPosition(); visible {
+		drawOnSkyArc(screen, progress, objects.SunSprite, tcell.ColorYellow, screenWidth, screenHeight)
+	}
+	if progress, visible := clock.MoonPosition(); visible {
+		drawOnSkyArc(screen, progress, objects.MoonSprite, tcell.ColorSilver, screenWidth, screenHeight)
+	}
+}
+
+// drawOnSkyArc рисует спрайт на дуге от левого края горизонта (progress=0) к правому (progress=1)
+func drawOnSkyArc(screen tcell.Screen, progress float64, sprite []string, color tcell.Color, screenWidth, screenHeight int) {
+	horizon := screenHeight / 2
+	x := int(progress * float64(screenWidth-len(sprite[0])))
+	y := horizon - int(math.Sin(math.Pi*progress)*float64(horizon-1))
+	for dy, line := range sprite {
+		for dx, ch := range []rune(line) {
+			if ch == ' ' {
+				continue
+			}
+			// Светило рисуется поверх неба, сохраняя его цвет
+			_, _, style, _ := screen.GetContent(x+dx, y+dy)
+			_, bg, _ := style.Decompose()
+			screen.SetContent(x+dx, y+dy, ch, nil, tcell.StyleDefault.Foreground(color).Background(bg))
 		}
-		
-		x1 := rocket.X - cameraX + width/3
-		x2 := rocket.X - cameraX + (2*width)/3 - len(upFlamePower)
-		y := rocket.Y - cameraY - 1
-		for i, r := range upFlamePower {
-			screen.SetContent(x1+i, y, r, nil, blueStyle)
-			screen.SetContent(x2+i, y, r, nil, blueStyle)
+	}
+}
+
+// DrawTrajectory рисует пунктиром предсказанную траекторию ракеты и отмечает место касания:
+// зелёным, если ракета сядет, и красным, если она разобьётся
+func DrawTrajectory(screen tcell.Screen, p *physics.Prediction, cameraX, cameraY, screenWidth, screenHeight int) {
+	dotStyle := tcell.StyleDefault.Foreground(tcell.ColorSilver)
+	for _, point := range p.Points {
+		x, y := int(math.Floor(point.X))-cameraX, int(math.Floor(point.Y))-cameraY
+		if x >= 0 && x < screenWidth && y >= 0 && y < screenHeight {
+			// Точка рисуется поверх неба или рельефа, сохраняя их фон
+			_, _, oldStyle, _ := screen.GetContent(x, y)
+			_, bg, _ := oldStyle.Decompose()
+			screen.SetContent(x, y, '·', nil, dotStyle.Background(bg))
 		}
 	}
+	if !p.Impact {
+		return
+	}
+
+	color := tcell.ColorGreen
+	if p.Landing().Crashed {
+		color = tcell.ColorRed
+	}
+	style := tcell.StyleDefault.Foreground(color).Background(tcell.ColorBlack).Bold(true)
+	x, y := int(math.Floor(p.ImpactX))-cameraX, int(math.Floor(p.ImpactY))-cameraY
+	if x >= 0 && x < screenWidth && y >= 0 && y < screenHeight {
+		screen.SetContent(x, y, '✕', nil, style)
+		label := fmt.Sprintf(" %.1fs %.1f ", p.ImpactTime, p.ImpactSpeed)
+		DrawText(screen, min(x+2, screenWidth-len(label)), y, label, style)
+	}
 }
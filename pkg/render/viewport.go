@@ -0,0 +1,57 @@
+package render
+
+import "github.com/gdamore/tcell/v2"
+
+// Viewport - прямоугольная часть экрана, которую можно отдать функциям рисования как целый экран.
+// Координаты смещаются на (X, Y), всё, что выходит за пределы области, отбрасывается.
+// Show ничего не делает: экран показывает тот, кто его разделил.
+type Viewport struct {
+	tcell.Screen
+	X, Y, Width, Height int
+}
+
+// NewViewport создаёт область экрана screen шириной width и высотой height с углом в (x, y)
+func NewViewport(screen tcell.Screen, x, y, width, height int) *Viewport {
+	return &Viewport{Screen: screen, X: x, Y: y, Width: width, Height: height}
+}
+
+// Size возвращает размер области
+func (v *Viewport) Size() (int, int) {
+	return v.Width, v.Height
+}
+
+func (v *Viewport) contains(x, y int) bool {
+	return x >= 0 && y >= 0 && x < v.Width && y < v.Height
+}
+
+// SetContent рисует клетку области, если она не выходит за её пределы
+func (v *Viewport) SetContent(x, y int, primary rune, combining []rune, style tcell.Style) {
+	if v.contains(x, y) {
+		v.Screen.SetContent(v.X+x, v.Y+y, primary, combining, style)
+	}
+}
+
+// GetContent возвращает клетку области; за её пределами - пустую клетку
+func (v *Viewport) GetContent(x, y int) (rune, []rune, tcell.Style, int) {
+	if !v.contains(x, y) {
+		return ' ', nil, tcell.StyleDefault, 1
+	}
+	return v.Screen.GetContent(v.X+x, v.Y+y)
+}
+
+// Clear очищает только область
+func (v *Viewport) Clear() {
+	v.Fill(' ', tcell.StyleDefault)
+}
+
+// Fill заполняет только область
+func (v *Viewport) Fill(r rune, style tcell.Style) {
+	for y := 0; y < v.Height; y++ {
+		for x := 0; x < v.Width; x++ {
+			v.Screen.SetContent(v.X+x, v.Y+y, r, nil, style)
+		}
+	}
+}
+
+// Show ничего не делает: содержимое области показывается вместе со всем экраном
+func (v *Viewport) Show() {}
@@ -0,0 +1,42 @@
+package script
+
+import "go.starlark.net/starlark"
+
+// Mission - скрипт миссии с обработчиками игровых событий. Все обработчики
+// необязательны: on_tick(t), on_stage_change(old, new, t), on_landing(t), on_crash(cause, t).
+type Mission struct {
+	*Script
+}
+
+// LoadMission загружает скрипт миссии
+func LoadMission(filename string, src interface{}, stepLimit uint64) (*Mission, error) {
+	s, err := load(filename, src, stepLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &Mission{Script: s}, nil
+}
+
+// OnTick вызывается каждый тик полёта
+func (m *Mission) OnTick(t Telemetry) error {
+	_, err := m.call("on_tick", t.value())
+	return err
+}
+
+// OnStageChange вызывается при переключении ступени
+func (m *Mission) OnStageChange(oldStage, newStage int, t Telemetry) error {
+	_, err := m.call("on_stage_change", starlark.MakeInt(oldStage), starlark.MakeInt(newStage), t.value())
+	return err
+}
+
+// OnLanding вызывается при мягком касании поверхности
+func (m *Mission) OnLanding(t Telemetry) error {
+	_, err := m.call("on_landing", t.value())
+	return err
+}
+
+// OnCrash вызывается при крушении
+func (m *Mission) OnCrash(cause string, t Telemetry) error {
+	_, err := m.call("on_crash", starlark.String(cause), t.value())
+	return err
+}
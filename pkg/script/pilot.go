@@ -0,0 +1,75 @@
+package script
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+)
+
+// Command - команда пилота на текущий тик
+type Command struct {
+	ThrustX, ThrustY float64
+	SetThrustX       bool // пилот задал ThrustX
+	SetThrustY       bool // пилот задал ThrustY
+	Stage            int  // ступень, которую нужно включить (-1 - не менять)
+}
+
+// Pilot - скрипт, который каждый тик получает телеметрию и возвращает команды тяги.
+// Скрипт обязан определить функцию pilot(t), возвращающую словарь с ключами
+// "thrust_y", "thrust_x" и "stage" (любой из них можно опустить) или None.
+type Pilot struct {
+	*Script
+}
+
+// LoadPilot загружает скрипт пилота
+func LoadPilot(filename string, src interface{}, stepLimit uint64) (*Pilot, error) {
+	s, err := load(filename, src, stepLimit)
+	if err != nil {
+		return nil, err
+	}
+	if !s.Has("pilot") {
+		return nil, fmt.Errorf("pilot script %s: function pilot(t) is not defined", filename)
+	}
+	return &Pilot{Script: s}, nil
+}
+
+// Tick вызывает pilot(t) и разбирает возвращённую команду
+func (p *Pilot) Tick(t Telemetry) (Command, error) {
+	cmd := Command{Stage: -1}
+	result, err := p.call("pilot", t.value())
+	if err != nil || result == starlark.None {
+		return cmd, err
+	}
+	dict, ok := result.(*starlark.Dict)
+	if !ok {
+		return cmd, fmt.Errorf("%s: pilot must return a dict or None, got %s", p.Name, result.Type())
+	}
+
+	if cmd.ThrustY, cmd.SetThrustY, err = floatField(dict, "thrust_y"); err != nil {
+		return cmd, fmt.Errorf("%s: %w", p.Name, err)
+	}
+	if cmd.ThrustX, cmd.SetThrustX, err = floatField(dict, "thrust_x"); err != nil {
+		return cmd, fmt.Errorf("%s: %w", p.Name, err)
+	}
+	if v, found, _ := dict.Get(starlark.String("stage")); found {
+		stage, err := starlark.AsInt32(v)
+		if err != nil {
+			return cmd, fmt.Errorf("%s: stage: %w", p.Name, err)
+		}
+		cmd.Stage = stage
+	}
+	return cmd, nil
+}
+
+// floatField читает числовое поле словаря; второй результат - было ли поле задано
+func floatField(dict *starlark.Dict, key string) (float64, bool, error) {
+	v, found, _ := dict.Get(starlark.String(key))
+	if !found {
+		return 0, false, nil
+	}
+	f, ok := starlark.AsFloat(v)
+	if !ok {
+		return 0, false, fmt.Errorf("%s must be a number, got %s", key, v.Type())
+	}
+	return f, true, nil
+}
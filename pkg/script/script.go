@@ -0,0 +1,108 @@
+// Package script встраивает Starlark для пользовательских пилотов и миссий.
+// Скрипты выполняются в песочнице: без доступа к файлам и сети, без load(),
+// а каждый вызов ограничен числом шагов интерпретатора. Память не ограничена:
+// одним шагом можно построить огромную строку или список, так что запускать
+// стоит только скрипты, которым доверяешь не меньше, чем самой игре.
+package script
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+)
+
+// DefaultStepLimit - ограничение числа шагов интерпретатора на один вызов скрипта
+const DefaultStepLimit = 100000
+
+// MaxMessages - сколько сообщений скрипт может отправить за один вызов; остальные отбрасываются
+const MaxMessages = 16
+
+// Script - загруженный Starlark-скрипт с глобальными функциями-обработчиками
+type Script struct {
+	Name      string
+	StepLimit uint64 // лимит шагов на вызов
+
+	globals  starlark.StringDict
+	messages []string
+	sent     int   // сообщений за текущий вызов
+	err      error // ошибка, после которой скрипт отключён
+}
+
+// load исполняет исходный код скрипта; верхний уровень тоже ограничен StepLimit
+func load(filename string, src interface{}, stepLimit uint64) (*Script, error) {
+	s := &Script{Name: filename, StepLimit: stepLimit}
+	predeclared := starlark.StringDict{
+		"message": starlark.NewBuiltin("message", s.builtinMessage),
+	}
+	globals, err := starlark.ExecFile(s.newThread(), filename, src, predeclared)
+	if err != nil {
+		return nil, fmt.Errorf("load script %s: %w", filename, err)
+	}
+	s.globals = globals
+	return s, nil
+}
+
+// Has возвращает true, если скрипт определяет функцию name
+func (s *Script) Has(name string) bool {
+	_, ok := s.globals[name].(starlark.Callable)
+	return ok
+}
+
+// Err возвращает ошибку, из-за которой скрипт отключён, или nil
+func (s *Script) Err() error {
+	return s.err
+}
+
+// TakeMessages возвращает накопленные скриптом сообщения для HUD и очищает очередь
+func (s *Script) TakeMessages() []string {
+	messages := s.messages
+	s.messages = nil
+	return messages
+}
+
+// call вызывает функцию скрипта name, если она определена, в отдельном потоке с лимитом шагов.
+// Первая ошибка отключает скрипт: дальше его функции не вызываются.
+func (s *Script) call(name string, args ...starlark.Value) (starlark.Value, error) {
+	fn, ok := s.globals[name].(starlark.Callable)
+	if !ok || s.err != nil {
+		return starlark.None, nil
+	}
+	result, err := starlark.Call(s.newThread(), fn, args, nil)
+	if err != nil {
+		s.err = fmt.Errorf("%s: %s: %w", s.Name, name, err)
+		return nil, s.err
+	}
+	return result, nil
+}
+
+// newThread создаёт поток исполнения с лимитом шагов; print() выводит в сообщения HUD,
+// а load() не поддерживается, поэтому скрипт не может подгрузить другие файлы
+func (s *Script) newThread() *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: s.Name,
+		Print: func(_ *starlark.Thread, msg string) {
+			s.addMessage(msg)
+		},
+	}
+	thread.SetMaxExecutionSteps(s.StepLimit)
+	s.sent = 0
+	return thread
+}
+
+// addMessage ставит сообщение в очередь, если скрипт не превысил MaxMessages за вызов
+func (s *Script) addMessage(msg string) {
+	s.sent++
+	if s.sent <= MaxMessages {
+		s.messages = append(s.messages, msg)
+	}
+}
+
+// builtinMessage реализует message(text): показать текст игроку
+func (s *Script) builtinMessage(_ *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var text string
+	if err := starlark.UnpackPositionalArgs(b.Name(), args, kwargs, 1, &text); err != nil {
+		return nil, err
+	}
+	s.addMessage(text)
+	return starlark.None, nil
+}
@@ -0,0 +1,103 @@
+package script
+
+import (
+	"strings"
+	"testing"
+)
+
+// testSteps - лимит шагов в тестах; бесконечный цикл упирается в него за доли секунды
+const testSteps = 10000
+
+func TestLoadSandbox(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		err  string // подстрока ошибки загрузки; пустая - скрипт загружается
+	}{
+		{"pilot", "def pilot(t):\n    return None\n", ""},
+		{"load", "load('other.star', 'x')\ndef pilot(t):\n    return None\n", "load not implemented"},
+		{"endless top level", "x = [i for i in range(1 << 60)]\ndef pilot(t):\n    return None\n", "too many steps"},
+		{"no pilot", "x = 1\n", "pilot(t) is not defined"},
+		{"syntax", "def pilot(t)\n", "got newline"},
+	}
+	for _, tc := range tests {
+		_, err := LoadPilot(tc.name+".star", tc.src, testSteps)
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s: %v", tc.name, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%s: error %v, want one containing %q", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestPilotTick(t *testing.T) {
+	tests := []struct {
+		name string
+		body string // тело pilot(t)
+		want Command
+		err  string // подстрока ошибки Tick; пустая - команда разобрана
+	}{
+		{"none", "return None", Command{Stage: -1}, ""},
+		{"all fields", "return {'thrust_y': 10, 'thrust_x': -1.5, 'stage': 2}",
+			Command{ThrustY: 10, ThrustX: -1.5, SetThrustY: true, SetThrustX: true, Stage: 2}, ""},
+		{"telemetry", "return {'thrust_y': t.altitude * 2}", Command{ThrustY: 200, SetThrustY: true, Stage: -1}, ""},
+		{"not a dict", "return [1]", Command{Stage: -1}, "must return a dict"},
+		{"string thrust", "return {'thrust_y': 'full'}", Command{Stage: -1}, "thrust_y must be a number"},
+		{"float stage", "return {'stage': 1.5}", Command{Stage: -1}, "stage"},
+		{"runtime error", "return 1 // 0", Command{Stage: -1}, "division by zero"},
+		{"endless loop", "for i in range(1 << 60):\n        pass", Command{Stage: -1}, "too many steps"},
+	}
+	for _, tc := range tests {
+		p, err := LoadPilot(tc.name+".star", "def pilot(t):\n    "+tc.body+"\n", testSteps)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		cmd, err := p.Tick(Telemetry{Altitude: 100})
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s: %v", tc.name, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%s: error %v, want one containing %q", tc.name, err, tc.err)
+		case cmd != tc.want:
+			t.Errorf("%s: command %+v, want %+v", tc.name, cmd, tc.want)
+		}
+	}
+}
+
+func TestBrokenScriptDisabled(t *testing.T) {
+	p, err := LoadPilot("broken.star", "def pilot(t):\n    message('tick')\n    return {'thrust_y': 1 // int(t.altitude)}\n", testSteps)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Tick(Telemetry{Altitude: 1}); err != nil || p.Err() != nil {
+		t.Fatalf("healthy tick: %v, %v", err, p.Err())
+	}
+	if _, err := p.Tick(Telemetry{}); err == nil {
+		t.Fatal("division by zero did not fail the tick")
+	}
+	p.TakeMessages()
+	// Отключённый скрипт больше не вызывается, даже если снова мог бы отработать
+	cmd, err := p.Tick(Telemetry{Altitude: 1})
+	if err != nil || cmd != (Command{Stage: -1}) || p.Err() == nil {
+		t.Errorf("tick after the failure = %+v, %v, Err() = %v; want an empty command and the script disabled", cmd, err, p.Err())
+	}
+	if messages := p.TakeMessages(); len(messages) != 0 {
+		t.Errorf("disabled script sent %v", messages)
+	}
+}
+
+func TestMessagesPerCall(t *testing.T) {
+	m, err := LoadMission("chatty.star", "def on_tick(t):\n    for i in range(1000):\n        message('m')\n        print('p')\n", DefaultStepLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for call := 0; call < 2; call++ {
+		if err := m.OnTick(Telemetry{}); err != nil {
+			t.Fatal(err)
+		}
+		if n := len(m.TakeMessages()); n != MaxMessages {
+			t.Errorf("call %d: %d messages, want %d", call, n, MaxMessages)
+		}
+	}
+}
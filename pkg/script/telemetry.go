@@ -0,0 +1,73 @@
+package script
+
+import (
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkstruct"
+
+	"github.com/shameoff/rocket-in-console/pkg/autopilot"
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+)
+
+// Telemetry - данные о полёте, доступные скриптам; те же величины, что показывает HUD
+type Telemetry struct {
+	Time        float64 // время полёта в секундах
+	Altitude    float64 // высота над уровнем GroundLevel
+	Height      float64 // высота низа ракеты над рельефом
+	Vx, Vy      float64 // скорости (положительная Vy - вниз)
+	ThrustX     float64
+	ThrustY     float64
+	Gravity     float64
+	Fuel        float64
+	FuelRate    float64 // расход топлива текущей ступени
+	Stage       int
+	StageName   string
+	Hull        float64
+	Engine      float64 // состояние двигателя текущей ступени
+	Temperature float64
+}
+
+// NewTelemetry снимает телеметрию с ракеты
+func NewTelemetry(r *objects.Rocket, groundLevel int, flightTime float64) Telemetry {
+	altitude := autopilot.Altitude(r, groundLevel)
+	stage := objects.RocketStages[r.ActiveStage]
+	return Telemetry{
+		Time:        flightTime,
+		Altitude:    altitude,
+		Height:      physics.HeightAboveTerrain(r),
+		Vx:          r.Vx,
+		Vy:          r.Vy,
+		ThrustX:     r.ThrustX,
+		ThrustY:     r.ThrustY,
+		Gravity:     physics.CalculateGravity(altitude),
+		Fuel:        r.Fuel,
+		FuelRate:    stage.FuelConsumptionRate,
+		Stage:       r.ActiveStage,
+		StageName:   stage.Name,
+		Hull:        r.Hull,
+		Engine:      r.ActiveEngineHealth(),
+		Temperature: r.Temperature,
+	}
+}
+
+// value превращает телеметрию в неизменяемую структуру Starlark
+func (t Telemetry) value() starlark.Value {
+	return starlarkstruct.FromStringDict(starlarkstruct.Default, starlark.StringDict{
+		"time":        starlark.Float(t.Time),
+		"altitude":    starlark.Float(t.Altitude),
+		"height":      starlark.Float(t.Height),
+		"vx":          starlark.Float(t.Vx),
+		"vy":          starlark.Float(t.Vy),
+		"thrust_x":    starlark.Float(t.ThrustX),
+		"thrust_y":    starlark.Float(t.ThrustY),
+		"gravity":     starlark.Float(t.Gravity),
+		"fuel":        starlark.Float(t.Fuel),
+		"fuel_rate":   starlark.Float(t.FuelRate),
+		"stage":       starlark.MakeInt(t.Stage),
+		"stage_name":  starlark.String(t.StageName),
+		"stages":      starlark.MakeInt(len(objects.RocketStages)),
+		"hull":        starlark.Float(t.Hull),
+		"engine":      starlark.Float(t.Engine),
+		"temperature": starlark.Float(t.Temperature),
+	})
+}
@@ -0,0 +1,89 @@
+package warnings
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/shameoff/rocket-in-console/pkg/events"
+)
+
+// DefaultHoldTime - сколько секунд предупреждение держится после включения, даже если причина ушла
+const DefaultHoldTime = 1.0
+
+// Raised - событие включения предупреждения
+type Raised struct {
+	Time float64
+	Rule Rule
+}
+
+func (e Raised) String() string {
+	return fmt.Sprintf("%s: %s at %.2fs", e.Rule.Level, e.Rule.Message, e.Time)
+}
+
+// Cleared - событие выключения предупреждения
+type Cleared struct {
+	Time float64
+	Rule Rule
+}
+
+func (e Cleared) String() string {
+	return fmt.Sprintf("%s cleared: %s at %.2fs", e.Rule.Level, e.Rule.Message, e.Time)
+}
+
+// Alert - включённое предупреждение
+type Alert struct {
+	Rule  Rule
+	Since float64 // время включения
+}
+
+// Monitor проверяет правила на каждом шаге полёта и публикует Raised и Cleared
+type Monitor struct {
+	Rules    []Rule
+	HoldTime float64 // минимальное время показа предупреждения
+
+	active map[string]*Alert
+}
+
+// NewMonitor создаёт монитор с правилами rules
+func NewMonitor(rules []Rule) *Monitor {
+	return &Monitor{Rules: rules, HoldTime: DefaultHoldTime, active: make(map[string]*Alert)}
+}
+
+// Reset молча снимает все предупреждения (например, при возрождении ракеты)
+func (m *Monitor) Reset() {
+	clear(m.active)
+}
+
+// Update проверяет правила по показаниям r на момент t и публикует изменения в bus
+func (m *Monitor) Update(r Readings, t float64, bus *events.Bus) {
+	for _, rule := range m.Rules {
+		value := metrics[rule.Metric](r)
+		alert, active := m.active[rule.Name]
+		switch {
+		case !active && rule.armed(r, false) && rule.beyond(value, rule.Set):
+			m.active[rule.Name] = &Alert{Rule: rule, Since: t}
+			bus.Publish(Raised{Time: t, Rule: rule})
+		case active && t-alert.Since >= m.HoldTime && (!rule.armed(r, true) || !rule.beyond(value, rule.Clear)):
+			delete(m.active, rule.Name)
+			bus.Publish(Cleared{Time: t, Rule: rule})
+		}
+	}
+}
+
+// Active возвращает включённые предупреждения: сначала более серьёзные, затем более ранние
+func (m *Monitor) Active() []Alert {
+	alerts := make([]Alert, 0, len(m.active))
+	for _, alert := range m.active {
+		alerts = append(alerts, *alert)
+	}
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Rule.Level != alerts[j].Rule.Level {
+			return alerts[i].Rule.Level > alerts[j].Rule.Level
+		}
+		if alerts[i].Since != alerts[j].Since {
+			return alerts[i].Since < alerts[j].Since
+		}
+		return alerts[i].Rule.Name < alerts[j].Rule.Name
+	})
+	return alerts
+}
@@ -0,0 +1,179 @@
+package warnings
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/shameoff/rocket-in-console/pkg/objects"
+	"github.com/shameoff/rocket-in-console/pkg/physics"
+)
+
+// Level - серьёзность предупреждения
+type Level int
+
+const (
+	Caution Level = iota // требует внимания
+	Warning              // требует немедленных действий
+)
+
+func (l Level) String() string {
+	if l == Warning {
+		return "warning"
+	}
+	return "caution"
+}
+
+// MarshalText записывает уровень в файл правил по имени
+func (l Level) MarshalText() ([]byte, error) {
+	return []byte(l.String()), nil
+}
+
+// UnmarshalText читает уровень из файла правил
+func (l *Level) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "caution":
+		*l = Caution
+	case "warning":
+		*l = Warning
+	default:
+		return fmt.Errorf("unknown warning level %q", text)
+	}
+	return nil
+}
+
+const (
+	fallingSpeed = 1.0 // скорость снижения, начиная с которой ракета считается падающей
+	guardMargin  = 0.5 // запас условий применимости для включённого предупреждения
+)
+
+// Readings - показания датчиков, по которым проверяются правила
+type Readings struct {
+	Fuel        float64 // доля топлива от полного бака
+	Descent     float64 // скорость снижения (отрицательная - подъём)
+	Speed       float64 // полная скорость
+	Height      float64 // высота над рельефом в игровых единицах
+	Thrust      float64 // отношение вертикальной тяги к гравитации (1 - зависание)
+	Temperature float64 // доля от температуры перегрева
+}
+
+// ReadingsOf снимает показания с ракеты r; fullTank - запас топлива при полном баке
+func ReadingsOf(r *objects.Rocket, groundLevel int, fullTank float64) Readings {
+	_, y := r.Position()
+	gravity := physics.CalculateGravity(float64(groundLevel) - y)
+	thrust := 0.0
+	if r.Fuel > 0 {
+		thrust = r.ThrustY / gravity
+	}
+	return Readings{
+		Fuel:        r.Fuel / fullTank,
+		Descent:     r.Vy,
+		Speed:       math.Hypot(r.Vx, r.Vy),
+		Height:      physics.HeightAboveTerrain(r),
+		Thrust:      thrust,
+		Temperature: r.Temperature / objects.OverheatTemperature,
+	}
+}
+
+// metrics - величины, которые могут проверять правила
+var metrics = map[string]func(Readings) float64{
+	"fuel":        func(r Readings) float64 { return r.Fuel },
+	"descent":     func(r Readings) float64 { return r.Descent },
+	"speed":       func(r Readings) float64 { return r.Speed },
+	"height":      func(r Readings) float64 { return r.Height },
+	"thrust":      func(r Readings) float64 { return r.Thrust },
+	"temperature": func(r Readings) float64 { return r.Temperature },
+}
+
+// Rule - правило предупреждения. Предупреждение включается, когда величина Metric
+// переходит порог Set, и выключается, только когда она вернётся за порог Clear:
+// зазор между порогами не даёт предупреждению мигать на границе.
+type Rule struct {
+	Name      string  `json:"name"`
+	Message   string  `json:"message"`
+	Level     Level   `json:"level"`
+	Metric    string  `json:"metric"`     // fuel, descent, speed, height, thrust или temperature
+	Above     bool    `json:"above"`      // срабатывает при значении выше Set (иначе - ниже)
+	Set       float64 `json:"set"`        // порог включения
+	Clear     float64 `json:"clear"`      // порог выключения
+	MaxHeight float64 `json:"max_height"` // правило действует только ниже этой высоты над рельефом (0 - везде)
+	Falling   bool    `json:"falling"`    // правило действует только при снижении
+	Disabled  bool    `json:"disabled"`   // отключает правило по умолчанию с тем же именем
+}
+
+// LowFuelRule - имя правила, включение которого публикуется ещё и как events.LowFuel
+const LowFuelRule = "low-fuel"
+
+// Defaults возвращает правила по умолчанию; safeDescent - наибольшая безопасная скорость посадки
+func Defaults(safeDescent float64) []Rule {
+	return []Rule{
+		{Name: LowFuelRule, Message: "LOW FUEL", Level: Caution, Metric: "fuel", Set: 0.15, Clear: 0.2},
+		{Name: "fuel-critical", Message: "FUEL CRITICAL", Level: Warning, Metric: "fuel", Set: 0.05, Clear: 0.07},
+		{Name: "sink-rate", Message: "SINK RATE", Level: Warning, Metric: "descent", Above: true,
+			Set: safeDescent * 0.75, Clear: safeDescent * 0.6, MaxHeight: 30},
+		{Name: "no-thrust", Message: "NO THRUST", Level: Caution, Metric: "thrust",
+			Set: 0.9, Clear: 1.0, MaxHeight: 100, Falling: true},
+		{Name: "overspeed", Message: "OVERSPEED", Level: Caution, Metric: "speed", Above: true, Set: 80, Clear: 70},
+		{Name: "overheat", Message: "OVERHEAT", Level: Warning, Metric: "temperature", Above: true, Set: 0.85, Clear: 0.75},
+	}
+}
+
+// LoadRules читает из JSON-файла path список правил и накладывает его на rules:
+// правило с тем же именем заменяется, с новым - добавляется, с disabled - убирается.
+// Порог Clear должен лежать по другую сторону от Set, чем срабатывание, иначе предупреждение мигало бы.
+func LoadRules(path string, rules []Rule) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var overrides []Rule
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	rules = append([]Rule(nil), rules...)
+	for _, o := range overrides {
+		if _, ok := metrics[o.Metric]; !ok && !o.Disabled {
+			return nil, fmt.Errorf("%s: rule %q: unknown metric %q", path, o.Name, o.Metric)
+		}
+		if !o.Disabled && o.beyond(o.Clear, o.Set) {
+			return nil, fmt.Errorf("%s: rule %q: clear %g is on the wrong side of set %g", path, o.Name, o.Clear, o.Set)
+		}
+		i := 0
+		for i < len(rules) && rules[i].Name != o.Name {
+			i++
+		}
+		switch {
+		case o.Disabled && i < len(rules):
+			rules = append(rules[:i], rules[i+1:]...)
+		case o.Disabled:
+		case i < len(rules):
+			rules[i] = o
+		default:
+			rules = append(rules, o)
+		}
+	}
+	return rules, nil
+}
+
+// armed возвращает true, если условия применимости правила выполнены.
+// Для уже включённого предупреждения условия смягчены, чтобы оно не мигало на их границе.
+func (rule *Rule) armed(r Readings, active bool) bool {
+	maxHeight, falling := rule.MaxHeight, fallingSpeed
+	if active {
+		maxHeight *= 1 + guardMargin
+		falling *= 1 - guardMargin
+	}
+	if rule.MaxHeight > 0 && r.Height > maxHeight {
+		return false
+	}
+	return !rule.Falling || r.Descent > falling
+}
+
+// beyond возвращает true, если значение перешло порог threshold в сторону срабатывания
+func (rule *Rule) beyond(value, threshold float64) bool {
+	if rule.Above {
+		return value > threshold
+	}
+	return value < threshold
+}
@@ -0,0 +1,189 @@
+package warnings
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shameoff/rocket-in-console/pkg/events"
+)
+
+// testTick - шаг времени в тестах монитора; двоичная дробь, чтобы время шагов складывалось точно
+const testTick = 0.125
+
+// recorder считает события предупреждений
+type recorder struct {
+	bus     *events.Bus
+	raised  int
+	cleared int
+}
+
+func newRecorder() *recorder {
+	rec := &recorder{bus: events.NewBus()}
+	events.Subscribe(rec.bus, func(Raised) { rec.raised++ })
+	events.Subscribe(rec.bus, func(Cleared) { rec.cleared++ })
+	return rec
+}
+
+// feed передаёт монитору показания по одному на шаг, начиная с момента start, и возвращает время после них
+func (rec *recorder) feed(m *Monitor, start float64, readings ...Readings) float64 {
+	t := start
+	for _, r := range readings {
+		m.Update(r, t, rec.bus)
+		rec.bus.Dispatch()
+		t += testTick
+	}
+	return t
+}
+
+// repeat возвращает n копий показаний r
+func repeat(r Readings, n int) []Readings {
+	readings := make([]Readings, n)
+	for i := range readings {
+		readings[i] = r
+	}
+	return readings
+}
+
+func TestHysteresis(t *testing.T) {
+	m := NewMonitor([]Rule{{Name: LowFuelRule, Metric: "fuel", Set: 0.15, Clear: 0.2}})
+	rec := newRecorder()
+	// Топливо колеблется вокруг порога включения, не доходя до порога выключения
+	var readings []Readings
+	for i := 0; i < 100; i++ {
+		fuel := 0.14
+		if i%2 == 1 {
+			fuel = 0.19
+		}
+		readings = append(readings, Readings{Fuel: fuel})
+	}
+	end := rec.feed(m, 0, readings...)
+	if rec.raised != 1 || rec.cleared != 0 {
+		t.Fatalf("oscillating fuel: %d raised and %d cleared, want 1 and 0", rec.raised, rec.cleared)
+	}
+	rec.feed(m, end, Readings{Fuel: 0.21})
+	if rec.cleared != 1 || len(m.Active()) != 0 {
+		t.Errorf("fuel above the clear threshold: %d cleared, %d active, want 1 and 0", rec.cleared, len(m.Active()))
+	}
+}
+
+func TestHoldTime(t *testing.T) {
+	m := NewMonitor([]Rule{{Name: "overspeed", Metric: "speed", Above: true, Set: 80, Clear: 70}})
+	rec := newRecorder()
+	// Короткий всплеск скорости: предупреждение держится HoldTime, а затем снимается
+	held := int(DefaultHoldTime/testTick) - 1
+	end := rec.feed(m, 0, append([]Readings{{Speed: 90}}, repeat(Readings{Speed: 10}, held)...)...)
+	if rec.raised != 1 || rec.cleared != 0 {
+		t.Fatalf("within the hold time: %d raised and %d cleared, want 1 and 0", rec.raised, rec.cleared)
+	}
+	rec.feed(m, end, Readings{Speed: 10})
+	if rec.cleared != 1 {
+		t.Errorf("after the hold time: %d cleared, want 1", rec.cleared)
+	}
+}
+
+func TestArmedGuards(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   Rule
+		raise  Readings // включает предупреждение
+		keep   Readings // за обычной границей условий, но в смягчённой - предупреждение держится
+		disarm Readings // за смягчённой границей - предупреждение снимается
+	}{
+		{
+			"height",
+			Rule{Name: "sink-rate", Metric: "descent", Above: true, Set: 15, Clear: 12, MaxHeight: 30},
+			Readings{Descent: 16, Height: 29},
+			Readings{Descent: 16, Height: 40},
+			Readings{Descent: 16, Height: 50},
+		},
+		{
+			"falling",
+			Rule{Name: "no-thrust", Metric: "thrust", Set: 0.9, Clear: 1.0, Falling: true},
+			Readings{Thrust: 0.5, Descent: 1.2},
+			Readings{Thrust: 0.5, Descent: 0.7},
+			Readings{Thrust: 0.5, Descent: 0.3},
+		},
+	}
+	for _, tc := range tests {
+		m := NewMonitor([]Rule{tc.rule})
+		rec := newRecorder()
+		// До включения условия строгие: показания keep предупреждение не включают
+		end := rec.feed(m, 0, tc.keep)
+		if rec.raised != 0 {
+			t.Errorf("%s: raised outside the rule's conditions", tc.name)
+		}
+		end = rec.feed(m, end, append([]Readings{tc.raise}, repeat(tc.keep, 20)...)...)
+		if rec.raised != 1 || rec.cleared != 0 {
+			t.Errorf("%s: within the relaxed conditions %d raised and %d cleared, want 1 and 0", tc.name, rec.raised, rec.cleared)
+		}
+		rec.feed(m, end, tc.disarm)
+		if rec.cleared != 1 {
+			t.Errorf("%s: beyond the relaxed conditions %d cleared, want 1", tc.name, rec.cleared)
+		}
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	defaults := Defaults(20)
+	tests := []struct {
+		name  string
+		json  string
+		check func(rules []Rule) bool
+		err   string // подстрока ошибки; пустая - правила загружаются
+	}{
+		{
+			"override",
+			`[{"name": "low-fuel", "message": "BINGO", "metric": "fuel", "set": 0.3, "clear": 0.35}]`,
+			func(rules []Rule) bool {
+				return len(rules) == len(defaults) && rules[0].Message == "BINGO" && rules[0].Set == 0.3
+			},
+			"",
+		},
+		{
+			"disable",
+			`[{"name": "overspeed", "disabled": true}, {"name": "no-such-rule", "disabled": true}]`,
+			func(rules []Rule) bool {
+				for _, r := range rules {
+					if r.Name == "overspeed" {
+						return false
+					}
+				}
+				return len(rules) == len(defaults)-1
+			},
+			"",
+		},
+		{
+			"add",
+			`[{"name": "low", "message": "LOW", "level": "warning", "metric": "height", "set": 5, "clear": 6}]`,
+			func(rules []Rule) bool {
+				last := rules[len(rules)-1]
+				return len(rules) == len(defaults)+1 && last.Name == "low" && last.Level == Warning
+			},
+			"",
+		},
+		{"unknown metric", `[{"name": "x", "metric": "altitude", "set": 1, "clear": 2}]`, nil, "unknown metric"},
+		{"unknown level", `[{"name": "x", "level": "panic", "metric": "fuel", "set": 1, "clear": 2}]`, nil, "unknown warning level"},
+		{"inverted below", `[{"name": "x", "metric": "fuel", "set": 0.2, "clear": 0.1}]`, nil, "wrong side"},
+		{"inverted above", `[{"name": "x", "metric": "speed", "above": true, "set": 70, "clear": 80}]`, nil, "wrong side"},
+	}
+	for _, tc := range tests {
+		path := filepath.Join(t.TempDir(), "warnings.json")
+		if err := os.WriteFile(path, []byte(tc.json), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		rules, err := LoadRules(path, defaults)
+		switch {
+		case tc.err == "" && err != nil:
+			t.Errorf("%s: %v", tc.name, err)
+		case tc.err != "" && (err == nil || !strings.Contains(err.Error(), tc.err)):
+			t.Errorf("%s: error %v, want one containing %q", tc.name, err, tc.err)
+		case tc.check != nil && !tc.check(rules):
+			t.Errorf("%s: rules %+v", tc.name, rules)
+		}
+	}
+	if defaults[0].Message != "LOW FUEL" || len(defaults) != len(Defaults(20)) {
+		t.Error("LoadRules changed the default rules")
+	}
+}